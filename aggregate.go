@@ -0,0 +1,237 @@
+package nflwp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// An Aggregator combines a team's per-game values for a metric into a single average.
+// Values are in the order the games were played, oldest first.
+type Aggregator interface {
+	Aggregate(Team string, Metric int, Values []float64) float64
+}
+
+// CumulativeMean counts every game the same. This is what AddData does.
+type CumulativeMean struct{}
+
+func (CumulativeMean) Aggregate(Team string, Metric int, Values []float64) float64 {
+	if len(Values) == 0 {
+		return 0
+	}
+	Sum := 0.0
+	for _, val := range Values {
+		Sum += val
+	}
+	return Sum / float64(len(Values))
+}
+
+// ExponentialDecay weighs each game half as much as the game played HalfLife games after it.
+// A HalfLife that isn't positive counts every game the same, like CumulativeMean.
+type ExponentialDecay struct {
+	HalfLife float64
+}
+
+func (e ExponentialDecay) Aggregate(Team string, Metric int, Values []float64) float64 {
+	if len(Values) == 0 {
+		return 0
+	}
+	if e.HalfLife <= 0 {
+		return CumulativeMean{}.Aggregate(Team, Metric, Values)
+	}
+	Sum, Weights := 0.0, 0.0
+	for i, val := range Values {
+		Weight := math.Pow(0.5, float64(len(Values)-1-i)/e.HalfLife)
+		Sum += Weight * val
+		Weights += Weight
+	}
+	return Sum / Weights
+}
+
+// RollingWindow only counts the last Games games.
+type RollingWindow struct {
+	Games int
+}
+
+func (r RollingWindow) Aggregate(Team string, Metric int, Values []float64) float64 {
+	if r.Games > 0 && len(Values) > r.Games {
+		Values = Values[len(Values)-r.Games:]
+	}
+	return CumulativeMean{}.Aggregate(Team, Metric, Values)
+}
+
+// PriorSeasonCarryover starts each team from last season's average, regressed toward the league average.
+// Regression is the fraction of the way back to the league average, so 0 keeps last season as is and 1 ignores it.
+// Weight is how many games last season counts as. The games this season are combined with the Base Aggregator,
+// which is CumulativeMean if it is nil.
+type PriorSeasonCarryover struct {
+	Prior      AllTeamData
+	Regression float64
+	Weight     float64
+	Base       Aggregator
+}
+
+// Last season's average for the team, regressed toward the league average.
+// Averages are taken the way AllTeamData.Average takes them, so OPPWPADJUST leaves out the first game.
+// Teams that didn't play last season get the league average.
+func (p PriorSeasonCarryover) PriorAverage(Team string, Metric int) float64 {
+	League, Teams := 0.0, 0.0
+	for key := range p.Prior {
+		if key == "BYE" {
+			continue
+		}
+		if Average, ok := p.Prior.Average(key, Metric); ok {
+			League += Average
+			Teams++
+		}
+	}
+	if Teams > 0 {
+		League /= Teams
+	}
+	if Average, ok := p.Prior.Average(Team, Metric); ok {
+		return League + (1-p.Regression)*(Average-League)
+	}
+	return League
+}
+
+func (p PriorSeasonCarryover) Aggregate(Team string, Metric int, Values []float64) float64 {
+	Base := p.Base
+	if Base == nil {
+		Base = CumulativeMean{}
+	}
+	Games := float64(len(Values))
+	if Games+p.Weight == 0 {
+		return 0
+	}
+	return (Games*Base.Aggregate(Team, Metric, Values) + p.Weight*p.PriorAverage(Team, Metric)) / (Games + p.Weight)
+}
+
+// A TeamHistory holds the TeamData of every game each team has played, oldest first.
+// PLAYINGTHISWEEK in each game holds the opponent.
+type TeamHistory map[string][][]float64
+
+// The values of a metric across a team's games, oldest first.
+func (h TeamHistory) Values(Team string, Metric int) []float64 {
+	Values := make([]float64, len(h[Team]))
+	for i, val := range h[Team] {
+		Values[i] = val[Metric]
+	}
+	return Values
+}
+
+// A SeasonBuilder gathers a season one game at a time.
+// Games holds every GameResult added so far, in order.
+// Method is how games fetched by AddWeek weigh each play, PERPLAY by default.
+// Sport is where AddWeek gets the games and which TeamRegistry codes PLAYINGTHISWEEK, NFL by default.
+// TeamData always holds the season so far with WPADJUST, STRAIGHTWPADJUST and OPPWPADJUST scaled
+// so dividing by GAMESPLAYED (GAMESPLAYED-1 for OPPWPADJUST) gives the Aggregator's average.
+type SeasonBuilder struct {
+	Aggregator Aggregator
+	Method     AdjustmentMethod
+	Sport      Sport
+	Games      []GameResult
+	History    TeamHistory
+	TeamData   AllTeamData
+}
+
+// Returns a SeasonBuilder that combines games with the given Aggregator.
+// If Agg is nil, CumulativeMean is used.
+func NewSeasonBuilder(Agg Aggregator) *SeasonBuilder {
+	return NewSeasonBuilderForSport(Agg, NFL)
+}
+
+// Like NewSeasonBuilder, but for the given sport. The "BYE" team is only added if the sport has one.
+func NewSeasonBuilderForSport(Agg Aggregator, ThisSport Sport) *SeasonBuilder {
+	if Agg == nil {
+		Agg = CumulativeMean{}
+	}
+	var TeamData AllTeamData = NewAllTeamData()
+	if ThisSport.HasBye {
+		TeamData["BYE"] = NewTeamData()
+	}
+	return &SeasonBuilder{Aggregator: Agg, Sport: ThisSport, History: make(TeamHistory), TeamData: TeamData}
+}
+
+// Add a game to the season and keep the GameResult.
+func (s *SeasonBuilder) AddGameResult(Result GameResult) {
+	s.Games = append(s.Games, Result)
+	s.AddGame(Result.TeamData(), Result.VisitingTeam, Result.HomeTeam)
+}
+
+// Add a game returned by GetDataForGameLink to the season.
+// Like GetTeamDataForWeek, each team gets its opponent's average WPADJUST added to OPPWPADJUST.
+func (s *SeasonBuilder) AddGame(ThisGame AllTeamData, VisitingTeam, HomeTeam string) {
+	_, ok := s.TeamData[VisitingTeam]
+	_, ok2 := s.TeamData[HomeTeam]
+	if ok && ok2 {
+		ThisGame[VisitingTeam][OPPWPADJUST] += s.TeamData[HomeTeam][WPADJUST] / s.TeamData[HomeTeam][GAMESPLAYED]
+		ThisGame[HomeTeam][OPPWPADJUST] += s.TeamData[VisitingTeam][WPADJUST] / s.TeamData[VisitingTeam][GAMESPLAYED]
+	}
+	ThisGame[VisitingTeam][PLAYINGTHISWEEK] = s.Sport.Teams.Float(HomeTeam)
+	ThisGame[HomeTeam][PLAYINGTHISWEEK] = s.Sport.Teams.Float(VisitingTeam)
+	for _, Team := range []string{VisitingTeam, HomeTeam} {
+		s.History[Team] = append(s.History[Team], ThisGame[Team])
+		s.rebuild(Team)
+	}
+}
+
+// Recompute a team's TeamData from its history.
+func (s *SeasonBuilder) rebuild(Team string) {
+	TeamData := NewTeamData()
+	for _, val := range s.History[Team] {
+		TeamData[GAMESPLAYED] += val[GAMESPLAYED]
+		TeamData[GAMESWON] += val[GAMESWON]
+	}
+	if Last, ok := s.TeamData[Team]; ok {
+		TeamData[SPREAD] = Last[SPREAD]
+		TeamData[PLAYINGTHISWEEK] = Last[PLAYINGTHISWEEK]
+	}
+	TeamData[WPADJUST] = s.Aggregator.Aggregate(Team, WPADJUST, s.History.Values(Team, WPADJUST)) * TeamData[GAMESPLAYED]
+	TeamData[STRAIGHTWPADJUST] = s.Aggregator.Aggregate(Team, STRAIGHTWPADJUST, s.History.Values(Team, STRAIGHTWPADJUST)) * TeamData[GAMESPLAYED]
+	// A team's first game never has an opponent adjustment.
+	if OPP := s.History.Values(Team, OPPWPADJUST); len(OPP) > 1 {
+		TeamData[OPPWPADJUST] = s.Aggregator.Aggregate(Team, OPPWPADJUST, OPP[1:]) * (TeamData[GAMESPLAYED] - 1)
+	}
+	s.TeamData[Team] = TeamData
+}
+
+// Add every game from the given week to the season.
+// Returns an error if the week isn't a number or the Sport's data source has no page listing the week's games,
+// and ErrNoGames if the page has no games.
+func (s *SeasonBuilder) AddWeek(Year, Week string) error {
+	WeekNumber, err := strconv.Atoi(Week)
+	if err != nil {
+		return fmt.Errorf("can't read the week %q", Week)
+	}
+	Links, err := s.Sport.GetGameLinks(Year, Week)
+	if err != nil {
+		return err
+	}
+	for _, Link := range Links {
+		Result := s.Sport.GetWeekGameResult(Link, Year, WeekNumber, s.Method)
+		if Result == nil {
+			fmt.Println("Error getting game data for link", Link)
+			continue
+		}
+		s.AddGameResult(*Result)
+	}
+	return nil
+}
+
+// Add every week of the year to the season.
+// If StopAtWeek > 0, then we stop gathering data after that week
+// Stops at the first week without games, which is only an error if it is the first week,
+// and at the first week AddWeek returns any other error for.
+func (s *SeasonBuilder) AddYear(Year string, StopAtWeek int) error {
+	Week := 1
+	for StopAtWeek < 0 || Week <= StopAtWeek {
+		if err := s.AddWeek(Year, strconv.Itoa(Week)); errors.Is(err, ErrNoGames) && Week > 1 {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		Week++
+	}
+	return nil
+}
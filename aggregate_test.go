@@ -0,0 +1,79 @@
+package nflwp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAggregators(t *testing.T) {
+	values := []float64{1, 2, 3, 4}
+	prior := NewAllTeamData()
+	prior["NWE"] = NewTeamData()
+	prior["NWE"][GAMESPLAYED] = 16
+	prior["NWE"][WPADJUST] = 16 * 0.2
+	prior["BUF"] = NewTeamData()
+	prior["BUF"][GAMESPLAYED] = 16
+	prior["BUF"][WPADJUST] = -16 * 0.2
+	aggregators := []Aggregator{
+		CumulativeMean{},
+		ExponentialDecay{HalfLife: 1},
+		ExponentialDecay{},
+		ExponentialDecay{HalfLife: -2},
+		RollingWindow{Games: 2},
+		PriorSeasonCarryover{Prior: prior, Regression: 0.5, Weight: 4},
+	}
+	// The exponential weights are 1/8, 1/4, 1/2 and 1, and without a positive half life they are all the same.
+	// Last season NWE averaged 0.2, the league averaged 0, so the regressed prior is 0.1.
+	expectedResults := []float64{2.5, (1.0/8 + 2.0/4 + 3.0/2 + 4) / (1.0/8 + 1.0/4 + 1.0/2 + 1), 2.5, 2.5, 3.5, (4*2.5 + 4*0.1) / 8}
+	for i := 0; i < len(aggregators); i++ {
+		result := aggregators[i].Aggregate("NWE", WPADJUST, values)
+		if math.Abs(result-expectedResults[i]) > 0.0005 {
+			t.Errorf("We got an unexpected result: %v instead of %v", result, expectedResults[i])
+		}
+	}
+	// OPPWPADJUST counts every game but the first, so 15 of NWE's 16.
+	prior["NWE"][OPPWPADJUST] = 15 * 0.2
+	if result := (PriorSeasonCarryover{Prior: prior}).PriorAverage("NWE", OPPWPADJUST); math.Abs(result-0.2) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", result, 0.2)
+	}
+}
+
+// A game in the given week with the home team's WPADJUST and the opposite for the visitors.
+// The home team wins if HomeAdjust is positive.
+func testResult(Week int, HomeTeam, VisitingTeam string, HomeAdjust float64) GameResult {
+	Result := GameResult{Week: Week, HomeTeam: HomeTeam, VisitingTeam: VisitingTeam, HomeWPADJUST: HomeAdjust, VisitingWPADJUST: -HomeAdjust,
+		HomeSTRAIGHTWPADJUST: 0.5 + HomeAdjust, VisitingSTRAIGHTWPADJUST: 0.5 - HomeAdjust}
+	if HomeAdjust > 0 {
+		Result.FinalWP = 1
+	}
+	return Result
+}
+
+func TestSeasonBuilderMatchesAddData(t *testing.T) {
+	games := [][]string{{"NWE", "BUF"}, {"NYJ", "MIA"}, {"NWE", "NYJ"}, {"BUF", "MIA"}, {"MIA", "NWE"}}
+	adjusts := []float64{0.1, -0.05, 0.2, 0.15, -0.3}
+	builder := NewSeasonBuilder(nil)
+	summed := NewAllTeamData()
+	summed["BYE"] = NewTeamData()
+	for i := 0; i < len(games); i++ {
+		builder.AddGameResult(testResult(i+1, games[i][0], games[i][1], adjusts[i]))
+		thisGame := testResult(i+1, games[i][0], games[i][1], adjusts[i]).TeamData()
+		if _, ok := summed[games[i][0]]; ok {
+			if _, ok := summed[games[i][1]]; ok {
+				thisGame[games[i][1]][OPPWPADJUST] += summed[games[i][0]][WPADJUST] / summed[games[i][0]][GAMESPLAYED]
+				thisGame[games[i][0]][OPPWPADJUST] += summed[games[i][1]][WPADJUST] / summed[games[i][1]][GAMESPLAYED]
+			}
+		}
+		summed.AddData(thisGame)
+	}
+	for team, val := range summed {
+		for _, metric := range []int{WPADJUST, STRAIGHTWPADJUST, GAMESPLAYED, GAMESWON, OPPWPADJUST} {
+			if math.Abs(builder.TeamData[team][metric]-val[metric]) > 0.0005 {
+				t.Errorf("We got an unexpected result for %v metric %v: %v instead of %v", team, metric, builder.TeamData[team][metric], val[metric])
+			}
+		}
+	}
+	if len(builder.History["NWE"]) != 3 || builder.History["NWE"][2][PLAYINGTHISWEEK] != GetTeamFloatFromAbbr("MIA") {
+		t.Errorf("We got an unexpected result: %v", builder.History["NWE"])
+	}
+}
@@ -0,0 +1,203 @@
+// Package backtest replays seasons week by week and grades spread picks
+// against the closing line.
+package backtest
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/thedadams/nflwp"
+)
+
+const (
+	WIN  = iota // The pick covered the spread
+	LOSS        // The pick did not cover the spread
+	PUSH        // The game landed on the spread
+)
+
+// A Game is a completed game along with its closing spread.
+// Spread is from the home team's point of view, so a negative spread means the home team was favored.
+// TeamData is the game's own contribution to the season, as returned by GetDataForGameLink.
+type Game struct {
+	Season        string
+	Week          int
+	HomeTeam      string
+	VisitingTeam  string
+	Spread        float64
+	HomeScore     float64
+	VisitingScore float64
+	TeamData      nflwp.AllTeamData
+}
+
+// A Pick is a side of the spread together with the probability that side covers.
+type Pick struct {
+	Home       bool
+	Confidence float64
+}
+
+// A Strategy looks at the season as it stood before the game's week and decides who to take.
+// Returning false means the strategy passes on the game.
+type Strategy interface {
+	Name() string
+	Pick(State nflwp.AllTeamData, ThisGame Game) (Pick, bool)
+}
+
+// A Result is the graded record of a strategy for a single season.
+type Result struct {
+	Strategy  string
+	Season    string
+	Wins      int
+	Losses    int
+	Pushes    int
+	Profit    float64
+	brierSum  float64
+	logLoss   float64
+	predicted int
+}
+
+// Grade a pick against the final score of a game.
+func Grade(ThisPick Pick, ThisGame Game) int {
+	Margin := ThisGame.HomeScore - ThisGame.VisitingScore + ThisGame.Spread
+	if !ThisPick.Home {
+		Margin = -Margin
+	}
+	if Margin > 0 {
+		return WIN
+	} else if Margin < 0 {
+		return LOSS
+	}
+	return PUSH
+}
+
+// Record the outcome of a pick in the result.
+// Bets are assumed to be laid at -110, so a win pays 100/110 of a unit and a loss costs one unit.
+func (r *Result) add(ThisPick Pick, Outcome int) {
+	switch Outcome {
+	case WIN:
+		r.Wins++
+		r.Profit += 100.0 / 110.0
+	case LOSS:
+		r.Losses++
+		r.Profit -= 1
+	default:
+		r.Pushes++
+		return
+	}
+	Actual := 0.0
+	if Outcome == WIN {
+		Actual = 1.0
+	}
+	Confidence := math.Min(math.Max(ThisPick.Confidence, 1e-15), 1-1e-15)
+	r.brierSum += (Confidence - Actual) * (Confidence - Actual)
+	r.logLoss -= Actual*math.Log(Confidence) + (1-Actual)*math.Log(1-Confidence)
+	r.predicted++
+}
+
+// The against the spread record as W-L-P.
+func (r Result) Record() string {
+	return fmt.Sprintf("%v-%v-%v", r.Wins, r.Losses, r.Pushes)
+}
+
+// Return on investment for the season. Pushes are refunded, so they don't count as money risked.
+func (r Result) ROI() float64 {
+	if r.Wins+r.Losses == 0 {
+		return 0
+	}
+	return r.Profit / float64(r.Wins+r.Losses)
+}
+
+// The mean squared error of the pick confidences. Pushes are left out.
+func (r Result) Brier() float64 {
+	if r.predicted == 0 {
+		return 0
+	}
+	return r.brierSum / float64(r.predicted)
+}
+
+// The mean log loss of the pick confidences. Pushes are left out.
+func (r Result) LogLoss() float64 {
+	if r.predicted == 0 {
+		return 0
+	}
+	return r.logLoss / float64(r.predicted)
+}
+
+// Given a list of games, replay each season one week at a time.
+// Every strategy only sees the games from earlier weeks when it makes its picks.
+// The results are sorted by season and then by the order the strategies were given.
+func Run(Games []Game, Strategies ...Strategy) []Result {
+	return RunForSport(Games, nflwp.NFL, Strategies...)
+}
+
+// Like Run, but the seasons are built with the sport's SeasonBuilder, so games from its odds files keep their opponents.
+// Give PredictorStrategy the same Sport.
+func RunForSport(Games []Game, ThisSport nflwp.Sport, Strategies ...Strategy) []Result {
+	Seasons := make(map[string][]Game)
+	for _, val := range Games {
+		Seasons[val.Season] = append(Seasons[val.Season], val)
+	}
+	SeasonNames := make([]string, 0, len(Seasons))
+	for key := range Seasons {
+		SeasonNames = append(SeasonNames, key)
+	}
+	sort.Strings(SeasonNames)
+	var Results []Result
+	for _, Season := range SeasonNames {
+		Results = append(Results, runSeason(Season, Seasons[Season], ThisSport, Strategies)...)
+	}
+	return Results
+}
+
+func runSeason(Season string, Games []Game, ThisSport nflwp.Sport, Strategies []Strategy) []Result {
+	sort.SliceStable(Games, func(i, j int) bool { return Games[i].Week < Games[j].Week })
+	Results := make([]Result, len(Strategies))
+	for i, val := range Strategies {
+		Results[i] = Result{Strategy: val.Name(), Season: Season}
+	}
+	Builder := nflwp.NewSeasonBuilderForSport(nil, ThisSport)
+	for Start := 0; Start < len(Games); {
+		End := Start
+		for End < len(Games) && Games[End].Week == Games[Start].Week {
+			End++
+		}
+		ThisWeek := Games[Start:End]
+		for _, ThisGame := range ThisWeek {
+			for i, Strat := range Strategies {
+				if ThisPick, ok := Strat.Pick(Builder.TeamData, ThisGame); ok {
+					Results[i].add(ThisPick, Grade(ThisPick, ThisGame))
+				}
+			}
+		}
+		for _, ThisGame := range ThisWeek {
+			AddGame(Builder, ThisGame)
+		}
+		Start = End
+	}
+	return Results
+}
+
+// Add the game to the season with SeasonBuilder.AddGame.
+func AddGame(Builder *nflwp.SeasonBuilder, ThisGame Game) {
+	if ThisGame.TeamData == nil {
+		return
+	}
+	// Copy the game's data so replaying the same games twice doesn't add the opponent adjustment twice.
+	GameData := nflwp.NewAllTeamData()
+	for key, val := range ThisGame.TeamData {
+		GameData[key] = append([]float64(nil), val...)
+	}
+	Builder.AddGame(GameData, ThisGame.VisitingTeam, ThisGame.HomeTeam)
+}
+
+// Write the results as a text table.
+func WriteReport(w io.Writer, Results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "Season\tStrategy\tATS\tROI\tBrier\tLog Loss")
+	for _, val := range Results {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%.3f\t%.4f\t%.4f\n", val.Season, val.Strategy, val.Record(), val.ROI(), val.Brier(), val.LogLoss())
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,129 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/thedadams/nflwp"
+)
+
+// A game in the season with the home team's WPADJUST and the opposite for the visitors.
+func testGame(Week int, HomeTeam, VisitingTeam string, Spread, HomeScore, VisitingScore, HomeAdjust float64) Game {
+	Result := nflwp.GameResult{Season: "2015", Week: Week, HomeTeam: HomeTeam, VisitingTeam: VisitingTeam, Spread: Spread, HomeScore: HomeScore, VisitingScore: VisitingScore,
+		HomeWPADJUST: HomeAdjust, VisitingWPADJUST: -HomeAdjust}
+	if HomeScore > VisitingScore {
+		Result.FinalWP = 1
+	}
+	return newGame(Result)
+}
+
+// A strategy that records how many games it has seen for the home team.
+type countingStrategy struct {
+	seen []float64
+}
+
+func (c *countingStrategy) Name() string {
+	return "Counting"
+}
+
+func (c *countingStrategy) Pick(State nflwp.AllTeamData, ThisGame Game) (Pick, bool) {
+	if val, ok := State[ThisGame.HomeTeam]; ok {
+		c.seen = append(c.seen, val[nflwp.GAMESPLAYED])
+	} else {
+		c.seen = append(c.seen, 0)
+	}
+	return Pick{Home: true, Confidence: 0.6}, true
+}
+
+func TestGrade(t *testing.T) {
+	games := []Game{
+		{HomeScore: 24, VisitingScore: 17, Spread: -3},
+		{HomeScore: 24, VisitingScore: 17, Spread: -7},
+		{HomeScore: 24, VisitingScore: 17, Spread: -10},
+		{HomeScore: 17, VisitingScore: 24, Spread: 10},
+	}
+	expectedResults := []int{WIN, PUSH, LOSS, WIN}
+	for i := 0; i < len(games); i++ {
+		result := Grade(Pick{Home: true}, games[i])
+		if result != expectedResults[i] {
+			t.Errorf("We got an unexpected result: %v instead of %v", result, expectedResults[i])
+		}
+	}
+	if result := Grade(Pick{Home: false}, games[2]); result != WIN {
+		t.Errorf("We got an unexpected result: %v instead of %v", result, WIN)
+	}
+}
+
+func TestRun(t *testing.T) {
+	games := []Game{
+		testGame(2, "NWE", "BUF", -3, 20, 10, 0.1),
+		testGame(1, "NWE", "PIT", -7, 20, 10, 0.1),
+		testGame(1, "NWE", "PIT", -10, 20, 10, 0.1),
+		testGame(3, "NWE", "NYJ", -10, 10, 20, -0.1),
+	}
+	strat := &countingStrategy{}
+	results := Run(games, strat)
+	if len(results) != 1 {
+		t.Fatalf("We got an unexpected result: %v instead of %v", len(results), 1)
+	}
+	// Both week 1 games must be picked before either is added to the state.
+	expectedSeen := []float64{0, 0, 2, 3}
+	for i := 0; i < len(expectedSeen); i++ {
+		if strat.seen[i] != expectedSeen[i] {
+			t.Errorf("We got an unexpected result: %v instead of %v", strat.seen[i], expectedSeen[i])
+		}
+	}
+	if results[0].Record() != "2-1-1" {
+		t.Errorf("We got an unexpected result: %v instead of %v", results[0].Record(), "2-1-1")
+	}
+	expectedROI := (2*100.0/110.0 - 1) / 3
+	if math.Abs(results[0].ROI()-expectedROI) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", results[0].ROI(), expectedROI)
+	}
+	expectedBrier := (0.16 + 0.16 + 0.36) / 3
+	if math.Abs(results[0].Brier()-expectedBrier) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", results[0].Brier(), expectedBrier)
+	}
+	expectedLogLoss := -(2*math.Log(0.6) + math.Log(0.4)) / 3
+	if math.Abs(results[0].LogLoss()-expectedLogLoss) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", results[0].LogLoss(), expectedLogLoss)
+	}
+}
+
+func TestRunForSport(t *testing.T) {
+	// College teams aren't in the NFL registry, so they only keep their opponents with the sport's SeasonBuilder.
+	games := []Game{
+		testGame(1, "ohio-state", "michigan", -7, 20, 10, 0.1),
+		testGame(2, "michigan", "ohio-state", 3, 20, 10, -0.1),
+		testGame(3, "ohio-state", "michigan", -3, 20, 10, 0.1),
+	}
+	strat := PredictorStrategy{Predictor: nflwp.EstSpreadPredictor{}, MinGames: 1, Sport: nflwp.NCAAF}
+	results := RunForSport(games, nflwp.NCAAF, strat)
+	if len(results) != 1 || results[0].Record() != "1-0-0" {
+		t.Fatalf("We got an unexpected result: %+v", results)
+	}
+	if results = RunForSport(games, nflwp.NCAAF, PredictorStrategy{Predictor: nflwp.EstSpreadPredictor{}, MinGames: 1, MinEdge: 100, Sport: nflwp.NCAAF}); results[0].Record() != "0-0-0" {
+		t.Errorf("We got an unexpected result: %v, there is no edge", results[0].Record())
+	}
+}
+
+func TestCoverProbability(t *testing.T) {
+	// College margins are wider, so the same edge is less sure.
+	if nfl, ncaaf := CoverProbability(-7, -3), CoverProbabilityForSport(-7, -3, nflwp.NCAAF); ncaaf >= nfl || ncaaf <= 0.5 {
+		t.Errorf("We got an unexpected result: %v for NCAAF and %v for the NFL", ncaaf, nfl)
+	}
+	estimates := []float64{-3, -7, 0}
+	spreads := []float64{-3, -3, -3}
+	for i := 0; i < len(estimates); i++ {
+		result := CoverProbability(estimates[i], spreads[i])
+		if estimates[i] == spreads[i] && math.Abs(result-0.5) > 0.0005 {
+			t.Errorf("We got an unexpected result: %v instead of %v", result, 0.5)
+		}
+		if estimates[i] < spreads[i] && result <= 0.5 {
+			t.Errorf("We got an unexpected result: %v, the home team should be more likely to cover", result)
+		}
+		if estimates[i] > spreads[i] && result >= 0.5 {
+			t.Errorf("We got an unexpected result: %v, the home team should be less likely to cover", result)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package backtest
+
+import (
+	"fmt"
+
+	"github.com/thedadams/nflwp"
+)
+
+// Given a sport and a year, load the games from the sport's odds file with Sport.LoadGamesFromSpreadFile.
+// The sport is its name or odds file name, like "NFL" or "Football".
+func LoadGamesFromSpreadFile(Sport string, Year int) ([]Game, error) {
+	ThisSport, ok := nflwp.GetSport(Sport)
+	if !ok {
+		return nil, fmt.Errorf("we don't know the sport %v", Sport)
+	}
+	SpreadGames, err := ThisSport.LoadGamesFromSpreadFile(Year)
+	if err != nil {
+		return nil, err
+	}
+	Games := make([]Game, 0, len(SpreadGames))
+	for _, val := range SpreadGames {
+		Games = append(Games, newGame(val.GameResult))
+	}
+	return Games, nil
+}
+
+// The Game for a completed game, with its contribution to the season from GameResult.TeamData.
+func newGame(Result nflwp.GameResult) Game {
+	return Game{
+		Season:        Result.Season,
+		Week:          Result.Week,
+		HomeTeam:      Result.HomeTeam,
+		VisitingTeam:  Result.VisitingTeam,
+		Spread:        Result.Spread,
+		HomeScore:     Result.HomeScore,
+		VisitingScore: Result.VisitingScore,
+		TeamData:      Result.TeamData(),
+	}
+}
@@ -0,0 +1,83 @@
+package backtest
+
+import (
+	"github.com/thedadams/nflwp"
+)
+
+// Given an estimated spread and the market spread, both from the home team's point of view,
+// return the probability the home team covers. A push counts as half a cover.
+func CoverProbability(EstimatedSpread, Spread float64) float64 {
+	return CoverProbabilityForSport(EstimatedSpread, Spread, nflwp.NFL)
+}
+
+// Like CoverProbability, but with the sport's MarginStdDev.
+func CoverProbabilityForSport(EstimatedSpread, Spread float64, ThisSport nflwp.Sport) float64 {
+	return nflwp.WinProbability(-Spread, EstimatedSpread, ThisSport.MarginStdDev)
+}
+
+// Take whichever side the estimated spread likes against the market spread.
+func pickFromEstimate(EstimatedSpread, Spread float64, ThisSport nflwp.Sport) Pick {
+	Prob := CoverProbabilityForSport(EstimatedSpread, Spread, ThisSport)
+	if Prob >= 0.5 {
+		return Pick{Home: true, Confidence: Prob}
+	}
+	return Pick{Home: false, Confidence: 1 - Prob}
+}
+
+// FavoriteStrategy always takes the favorite at a coin flip.
+// It is a baseline to compare the other strategies against.
+type FavoriteStrategy struct{}
+
+func (FavoriteStrategy) Name() string {
+	return "Favorite"
+}
+
+func (FavoriteStrategy) Pick(State nflwp.AllTeamData, ThisGame Game) (Pick, bool) {
+	return Pick{Home: ThisGame.Spread <= 0, Confidence: 0.5}, true
+}
+
+// PredictorStrategy takes whichever side a nflwp.Predictor likes against the closing spread.
+// Games are skipped until both teams have played more than MinGames games
+// and as many games as nflwp.PredictorMinGames says the Predictor needs,
+// and when the Predictor's spread is less than MinEdge points away from the market.
+// Sport codes the opponents and turns spreads into cover probabilities. It is the NFL if it is left out.
+type PredictorStrategy struct {
+	Predictor nflwp.Predictor
+	MinGames  float64
+	MinEdge   float64
+	Sport     nflwp.Sport
+}
+
+func (s PredictorStrategy) Name() string {
+	return s.Predictor.Name()
+}
+
+// The Sport, or the NFL if it is left out.
+func (s PredictorStrategy) sport() nflwp.Sport {
+	if s.Sport.Teams == nil {
+		return nflwp.NFL
+	}
+	return s.Sport
+}
+
+func (s PredictorStrategy) Pick(State nflwp.AllTeamData, ThisGame Game) (Pick, bool) {
+	Home, ok := State[ThisGame.HomeTeam]
+	if !ok {
+		Home = nflwp.NewTeamData()
+	}
+	Visitor, ok := State[ThisGame.VisitingTeam]
+	if !ok {
+		Visitor = nflwp.NewTeamData()
+	}
+	MinGames := nflwp.PredictorMinGames(s.Predictor)
+	if Home[nflwp.GAMESPLAYED] <= s.MinGames || Visitor[nflwp.GAMESPLAYED] <= s.MinGames || Home[nflwp.GAMESPLAYED] < MinGames || Visitor[nflwp.GAMESPLAYED] < MinGames {
+		return Pick{}, false
+	}
+	ThisSport := s.sport()
+	TeamData := State.ForGameForSport(nflwp.UpcomingGame{HomeTeam: ThisGame.HomeTeam, VisitingTeam: ThisGame.VisitingTeam, Spread: ThisGame.Spread}, ThisSport)
+	EstSpread := s.Predictor.Predict(TeamData, ThisGame.HomeTeam, ThisGame.VisitingTeam).Spread
+	if EstSpread-ThisGame.Spread < s.MinEdge && ThisGame.Spread-EstSpread < s.MinEdge {
+		return Pick{}, false
+	}
+	return pickFromEstimate(EstSpread, ThisGame.Spread, ThisSport), true
+}
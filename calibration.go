@@ -0,0 +1,258 @@
+package nflwp
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// The win probability models a CalibrationReport compares.
+const (
+	PFRMODEL    = "PFR"    // The chart's own win probability
+	SPREADMODEL = "Spread" // The spread alone, decaying over the game the way Sport.AdjustedStartingProbability has it
+)
+
+// A CalibrationBucket is the points whose forecast fell in [Low, High), with the average forecast and how often the home team won.
+type CalibrationBucket struct {
+	Low      float64
+	High     float64
+	Count    float64
+	Forecast float64
+	Observed float64
+}
+
+// A BrierScore is the mean squared error of a model's forecasts along with Murphy's decomposition into
+// Reliability (lower is better calibrated), Resolution (higher tells games apart better) and Uncertainty (the outcomes' own variance).
+// Brier is about Reliability - Resolution + Uncertainty; it is exact when every forecast in a bucket is the same.
+type BrierScore struct {
+	Brier       float64
+	Reliability float64
+	Resolution  float64
+	Uncertainty float64
+}
+
+// A Calibration is how a model did over a set of points. Quarter is 0 for every point, 5 for overtime and the quarter otherwise.
+type Calibration struct {
+	Model   string
+	Quarter int
+	Points  float64
+	Buckets []CalibrationBucket
+	Score   BrierScore
+}
+
+// A CalibrationReport has a Calibration for each model over the whole game followed by one for each quarter.
+type CalibrationReport []Calibration
+
+// A point of a game with each model's forecast and the result.
+type calibrationPoint struct {
+	Quarter   int
+	Forecasts map[string]float64
+	Outcome   float64
+}
+
+// The home team's result: 1 for a win, 0 for a loss and 0.5 for a tie. Returns false if we can't tell.
+// A game that is over with its chart at 0.5 or with the scores level, 0-0 included, is a tie.
+// A chart at 0.5 before the clock runs out is a game still going.
+func gameOutcome(Result GameResult) (float64, bool) {
+	Final := Result.FinalWP
+	Over := false
+	if len(Result.Points) > 0 {
+		Last := Result.Points[len(Result.Points)-1]
+		Final = Last.HomeWP
+		Over = Last.Elapsed > 0 && Last.Remaining == 0
+	}
+	switch {
+	case Final == 1 || Final == 0:
+		return Final, true
+	case Over && (Final == 0.5 || Result.HomeScore == Result.VisitingScore):
+		return 0.5, true
+	}
+	return 0, false
+}
+
+// Given games with their charts, compare PFR's win probability and the spread's to how the games ended, with the forecasts
+// put in the given number of equal buckets. The last point of each game is the result, so it isn't counted,
+// and games whose result we can't tell are skipped.
+func Calibrate(Games []GameResult, Buckets int) CalibrationReport {
+	return CalibrateForSport(Games, Buckets, NFL)
+}
+
+// Like Calibrate, but the spread's win probability is found with the sport's clock, and overtime as long as it was
+// in the week of each game, see Sport.ForWeek.
+func CalibrateForSport(Games []GameResult, Buckets int, ThisSport Sport) CalibrationReport {
+	if Buckets < 1 {
+		Buckets = 10
+	}
+	var Points []calibrationPoint
+	for _, Game := range Games {
+		Outcome, ok := gameOutcome(Game)
+		if !ok || len(Game.Points) < 2 {
+			continue
+		}
+		GameSport := ThisSport.ForWeek(Game.Season, Game.Week, "")
+		Adjustment := 0.0
+		for _, val := range Game.Points[:len(Game.Points)-1] {
+			Adjustment = GameSport.AdjustedStartingProbability(Game.Spread, val.PlayInfo, Adjustment)
+			Points = append(Points, calibrationPoint{Quarter: int(val.Quarter), Outcome: Outcome,
+				Forecasts: map[string]float64{PFRMODEL: val.HomeWP, SPREADMODEL: Adjustment}})
+		}
+	}
+	var Report CalibrationReport
+	for _, Quarter := range []int{0, 1, 2, 3, 4, 5} {
+		for _, Model := range []string{PFRMODEL, SPREADMODEL} {
+			Report = append(Report, calibrate(Points, Model, Quarter, Buckets))
+		}
+	}
+	return Report
+}
+
+func calibrate(Points []calibrationPoint, Model string, Quarter, Buckets int) Calibration {
+	Result := Calibration{Model: Model, Quarter: Quarter, Buckets: make([]CalibrationBucket, Buckets)}
+	Outcomes := make([]float64, Buckets)
+	Mean := 0.0
+	for i := range Result.Buckets {
+		Result.Buckets[i].Low = float64(i) / float64(Buckets)
+		Result.Buckets[i].High = float64(i+1) / float64(Buckets)
+	}
+	for _, val := range Points {
+		if Quarter != 0 && val.Quarter != Quarter {
+			continue
+		}
+		Forecast := val.Forecasts[Model]
+		i := int(Forecast * float64(Buckets))
+		if i >= Buckets {
+			i = Buckets - 1
+		} else if i < 0 {
+			i = 0
+		}
+		Result.Buckets[i].Count++
+		Result.Buckets[i].Forecast += Forecast
+		Outcomes[i] += val.Outcome
+		Result.Points++
+		Result.Score.Brier += (Forecast - val.Outcome) * (Forecast - val.Outcome)
+		Mean += val.Outcome
+	}
+	if Result.Points == 0 {
+		return Result
+	}
+	Mean /= Result.Points
+	Result.Score.Brier /= Result.Points
+	Result.Score.Uncertainty = Mean * (1 - Mean)
+	for i := range Result.Buckets {
+		Bucket := &Result.Buckets[i]
+		if Bucket.Count == 0 {
+			continue
+		}
+		Bucket.Forecast /= Bucket.Count
+		Bucket.Observed = Outcomes[i] / Bucket.Count
+		Result.Score.Reliability += Bucket.Count * (Bucket.Forecast - Bucket.Observed) * (Bucket.Forecast - Bucket.Observed) / Result.Points
+		Result.Score.Resolution += Bucket.Count * (Bucket.Observed - Mean) * (Bucket.Observed - Mean) / Result.Points
+	}
+	return Result
+}
+
+// The Calibration for the model and quarter, or false if the report doesn't have it.
+func (r CalibrationReport) Get(Model string, Quarter int) (Calibration, bool) {
+	for _, val := range r {
+		if val.Model == Model && val.Quarter == Quarter {
+			return val, true
+		}
+	}
+	return Calibration{}, false
+}
+
+// The name of a quarter in the report.
+func quarterName(Quarter int) string {
+	switch Quarter {
+	case 0:
+		return "All"
+	case 5:
+		return "OT"
+	}
+	return "Q" + strconv.Itoa(Quarter)
+}
+
+// Write the reliability diagrams as CSV with a header row, one row per model, quarter and bucket.
+// Empty buckets are left out.
+func (r CalibrationReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Model", "Quarter", "Low", "High", "Count", "Forecast", "Observed"})
+	for _, val := range r {
+		for _, Bucket := range val.Buckets {
+			if Bucket.Count == 0 {
+				continue
+			}
+			cw.Write([]string{val.Model, quarterName(val.Quarter), strconv.FormatFloat(Bucket.Low, 'f', 2, 64), strconv.FormatFloat(Bucket.High, 'f', 2, 64),
+				strconv.FormatFloat(Bucket.Count, 'f', 0, 64), strconv.FormatFloat(Bucket.Forecast, 'f', 4, 64), strconv.FormatFloat(Bucket.Observed, 'f', 4, 64)})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Write each model's Brier score and its decomposition for the whole game and each quarter as an aligned text table.
+func (r CalibrationReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if err := writeTabbed(tw, []string{"Model", "Quarter", "Points", "Brier", "Reliability", "Resolution", "Uncertainty"}); err != nil {
+		return err
+	}
+	for _, val := range r {
+		if val.Points == 0 {
+			continue
+		}
+		err := writeTabbed(tw, []string{val.Model, quarterName(val.Quarter), strconv.FormatFloat(val.Points, 'f', 0, 64),
+			strconv.FormatFloat(val.Score.Brier, 'f', 4, 64), strconv.FormatFloat(val.Score.Reliability, 'f', 4, 64),
+			strconv.FormatFloat(val.Score.Resolution, 'f', 4, 64), strconv.FormatFloat(val.Score.Uncertainty, 'f', 4, 64)})
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// The colors the models are drawn in.
+var calibrationColors = map[string]string{PFRMODEL: "#1f77b4", SPREADMODEL: "#d62728"}
+
+// Write the reliability diagram for a quarter, 0 for the whole game, as an SVG.
+// Each model is a line through its buckets' average forecast and observed win rate; a perfectly calibrated model is on the diagonal.
+func (r CalibrationReport) WriteSVG(w io.Writer, Quarter int) error {
+	const Size, Margin = 400.0, 50.0
+	X := func(p float64) float64 { return Margin + p*Size }
+	Y := func(p float64) float64 { return Margin + (1-p)*Size }
+	// A bufio.Writer keeps the first error, so Flush returns it.
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" font-family=\"sans-serif\" font-size=\"12\">\n", Size+2*Margin, Size+2*Margin)
+	fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\" text-anchor=\"middle\" font-size=\"14\">Calibration, %v</text>\n", X(0.5), Margin/2, quarterName(Quarter))
+	fmt.Fprintf(bw, "<rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"none\" stroke=\"#000\"/>\n", X(0), Y(1), Size, Size)
+	fmt.Fprintf(bw, "<line x1=\"%v\" y1=\"%v\" x2=\"%v\" y2=\"%v\" stroke=\"#999\" stroke-dasharray=\"4\"/>\n", X(0), Y(0), X(1), Y(1))
+	for i := 0; i <= 10; i++ {
+		p := float64(i) / 10
+		fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\" text-anchor=\"middle\">%.1f</text>\n", X(p), Y(0)+16, p)
+		fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\" text-anchor=\"end\">%.1f</text>\n", X(0)-6, Y(p)+4, p)
+	}
+	fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\" text-anchor=\"middle\">Forecast home WP</text>\n", X(0.5), Y(0)+36)
+	fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\" text-anchor=\"middle\" transform=\"rotate(-90 %v %v)\">Home win rate</text>\n", Margin/3, Y(0.5), Margin/3, Y(0.5))
+	Legend := 0
+	for _, val := range r {
+		if val.Quarter != Quarter || val.Points == 0 {
+			continue
+		}
+		Color := calibrationColors[val.Model]
+		Points := ""
+		for _, Bucket := range val.Buckets {
+			if Bucket.Count == 0 {
+				continue
+			}
+			Points += fmt.Sprintf("%.1f,%.1f ", X(Bucket.Forecast), Y(Bucket.Observed))
+			fmt.Fprintf(bw, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"3\" fill=\"%v\"/>\n", X(Bucket.Forecast), Y(Bucket.Observed), Color)
+		}
+		fmt.Fprintf(bw, "<polyline points=\"%v\" fill=\"none\" stroke=\"%v\" stroke-width=\"2\"/>\n", Points, Color)
+		fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\" fill=\"%v\">%v (Brier %.4f)</text>\n", X(0.03), Y(0.95)+float64(Legend)*16, Color, val.Model, val.Score.Brier)
+		Legend++
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
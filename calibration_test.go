@@ -0,0 +1,116 @@
+package nflwp
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func calibrationGames() []GameResult {
+	points := func(First, Second, Final float64) []WPPoint {
+		return []WPPoint{
+			{HomeWP: First, PlayInfo: "\"Q1 15:00 CHI 0-GNB 0\"", Quarter: 1, Remaining: 60, TotalMinutes: 60},
+			{HomeWP: Second, PlayInfo: "\"Q3 15:00 CHI 0-GNB 7\"", Quarter: 3, Remaining: 30, Elapsed: 30, TotalMinutes: 60},
+			{HomeWP: Final, PlayInfo: "\"Q4 0:00 CHI 0-GNB 7\"", Quarter: 4, Elapsed: 60, TotalMinutes: 60},
+		}
+	}
+	unfinished := points(0.7, 0.6, 0.6)
+	unfinished[2].Remaining, unfinished[2].Elapsed = 2, 58
+	return []GameResult{
+		{HomeTeam: "GNB", VisitingTeam: "CHI", Spread: -3, Points: points(0.6, 0.8, 1)},
+		{HomeTeam: "CHI", VisitingTeam: "GNB", Spread: 3, Points: points(0.3, 0.1, 0)},
+		// We can't tell how this one ended, so it is skipped.
+		{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -7, Points: unfinished},
+	}
+}
+
+func TestGameOutcome(t *testing.T) {
+	over := []WPPoint{{HomeWP: 0.6, Quarter: 5, Elapsed: 75, TotalMinutes: 75}}
+	games := []GameResult{
+		{FinalWP: 1},
+		{Points: []WPPoint{{HomeWP: 0.5, Quarter: 5, Elapsed: 75, TotalMinutes: 75}}},
+		// A chart at 0.5 with time left isn't over.
+		{Points: []WPPoint{{HomeWP: 0.5, Quarter: 2, Elapsed: 20, Remaining: 40, TotalMinutes: 60}}},
+		// A scoreless tie, over with the chart still short of 0.5.
+		{Points: over},
+		{HomeScore: 20, VisitingScore: 20, Points: over},
+		{HomeScore: 20, VisitingScore: 17, Points: over},
+	}
+	expectedResults := []float64{1, 0.5, -1, 0.5, 0.5, -1}
+	for i := range games {
+		result, ok := gameOutcome(games[i])
+		if ok != (expectedResults[i] >= 0) || (ok && result != expectedResults[i]) {
+			t.Errorf("We got an unexpected result for %+v: %v %v instead of %v", games[i], result, ok, expectedResults[i])
+		}
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	report := Calibrate(calibrationGames(), 2)
+	if len(report) != 12 {
+		t.Fatalf("We got an unexpected result: %v calibrations instead of 12", len(report))
+	}
+	pfr, ok := report.Get(PFRMODEL, 0)
+	if !ok || pfr.Points != 4 {
+		t.Fatalf("We got an unexpected result: %+v", pfr)
+	}
+	expectedBuckets := []CalibrationBucket{{Low: 0, High: 0.5, Count: 2, Forecast: 0.2, Observed: 0}, {Low: 0.5, High: 1, Count: 2, Forecast: 0.7, Observed: 1}}
+	for i, val := range pfr.Buckets {
+		if val.Count != expectedBuckets[i].Count || math.Abs(val.Forecast-expectedBuckets[i].Forecast) > 1e-9 || val.Observed != expectedBuckets[i].Observed {
+			t.Errorf("We got an unexpected result: %+v instead of %+v", val, expectedBuckets[i])
+		}
+	}
+	expectedScore := BrierScore{Brier: 0.075, Reliability: 0.065, Resolution: 0.25, Uncertainty: 0.25}
+	if math.Abs(pfr.Score.Brier-expectedScore.Brier) > 1e-9 || math.Abs(pfr.Score.Reliability-expectedScore.Reliability) > 1e-9 ||
+		math.Abs(pfr.Score.Resolution-expectedScore.Resolution) > 1e-9 || math.Abs(pfr.Score.Uncertainty-expectedScore.Uncertainty) > 1e-9 {
+		t.Errorf("We got an unexpected result: %+v instead of %+v", pfr.Score, expectedScore)
+	}
+	// At kickoff the spread model is the pregame win probability.
+	spread, _ := report.Get(SPREADMODEL, 1)
+	if spread.Points != 2 || spread.Buckets[0].Count != 1 || math.Abs(spread.Buckets[0].Forecast-WinProbability(0, 3, STDDEV)) > 1e-9 ||
+		math.Abs(spread.Buckets[1].Forecast-WinProbability(0, -3, STDDEV)) > 1e-9 {
+		t.Errorf("We got an unexpected result: %+v", spread)
+	}
+	// College margins are wider, so the spread means less.
+	college, _ := CalibrateForSport(calibrationGames(), 2, NCAAF).Get(SPREADMODEL, 1)
+	if college.Points != 2 || math.Abs(college.Buckets[0].Forecast-WinProbability(0, 3, NCAAF.MarginStdDev)) > 1e-9 {
+		t.Errorf("We got an unexpected result: %+v", college)
+	}
+	if third, _ := report.Get(PFRMODEL, 3); third.Points != 2 || third.Buckets[1].Forecast != 0.8 {
+		t.Errorf("We got an unexpected result: %+v", third)
+	}
+	if overtime, _ := report.Get(PFRMODEL, 5); overtime.Points != 0 {
+		t.Errorf("We got an unexpected result: %+v", overtime)
+	}
+}
+
+func TestCalibrationWriters(t *testing.T) {
+	report := Calibrate(calibrationGames(), 2)
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "Model,Quarter,Low,High,Count,Forecast,Observed" || lines[1] != "PFR,All,0.00,0.50,2,0.2000,0.0000" {
+		t.Errorf("We got an unexpected result: %v", lines[:2])
+	}
+	buf.Reset()
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Reliability") || strings.Contains(buf.String(), "OT") {
+		t.Errorf("We got an unexpected result: %v", buf.String())
+	}
+	buf.Reset()
+	if err := report.WriteSVG(&buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	if !strings.HasPrefix(svg, "<svg") || strings.Count(svg, "<polyline") != 2 || !strings.Contains(svg, "PFR (Brier 0.0750)") {
+		t.Errorf("We got an unexpected result: %v", svg)
+	}
+	if err := report.WriteSVG(failingWriter{}, 0); err == nil {
+		t.Errorf("We expected an error writing to a failing writer")
+	}
+}
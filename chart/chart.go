@@ -0,0 +1,192 @@
+// Package chart draws a game's win probability curve against the win probability the spread alone gives,
+// shading the area between them, which is what WPADJUST averages. Charts are drawn as SVG, or as PNG without the text.
+// It also draws a season's team ratings week by week as SVG line charts and heat maps, and as HTML pages with a ranked table.
+package chart
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+
+	"github.com/thedadams/nflwp"
+)
+
+// A Point is a point of the chart. Elapsed is minutes of game clock, HomeWP is the chart's win probability
+// and Baseline is what the spread gives at the same time, both for the home team.
+type Point struct {
+	Elapsed  float64
+	HomeWP   float64
+	Baseline float64
+}
+
+// A Chart is a game ready to be drawn. The game has Periods periods of PeriodMinutes, and anything after them is overtime.
+// A Chart without PeriodMinutes has the NFL's periods.
+type Chart struct {
+	Title         string
+	HomeTeam      string
+	VisitingTeam  string
+	TotalMinutes  float64
+	Periods       float64
+	PeriodMinutes float64
+	WPADJUST      float64
+	Points        []Point
+}
+
+// The colors of the chart. The area is shaded Above where the home team is doing better than the spread and Below where it is worse.
+var (
+	WPColor       = "#1f77b4"
+	BaselineColor = "#555555"
+	AboveColor    = "#2ca02c"
+	BelowColor    = "#d62728"
+)
+
+// Given an NFL game with its chart, make the Chart, with the baseline found the same way FindAdjustedStartingProbability does for WPADJUST.
+// Points without a clock are spread out evenly over the game. See NewForSport for other sports.
+func New(Result nflwp.GameResult) Chart {
+	return NewForSport(Result, nflwp.NFL)
+}
+
+// Like New, but the game is as long as the sport's and the baseline is the sport's AdjustedStartingProbability.
+func NewForSport(Result nflwp.GameResult, ThisSport nflwp.Sport) Chart {
+	c := Chart{
+		Title:         fmt.Sprintf("%v at %v, %v", Result.VisitingTeam, Result.HomeTeam, Result.Date),
+		HomeTeam:      Result.HomeTeam,
+		VisitingTeam:  Result.VisitingTeam,
+		TotalMinutes:  ThisSport.GameMinutes(),
+		Periods:       ThisSport.Periods,
+		PeriodMinutes: ThisSport.PeriodMinutes,
+		WPADJUST:      Result.HomeWPADJUST,
+		Points:        make([]Point, len(Result.Points)),
+	}
+	Clock := false
+	for _, val := range Result.Points {
+		c.TotalMinutes = math.Max(c.TotalMinutes, val.TotalMinutes)
+		Clock = Clock || val.Elapsed > 0
+	}
+	Baseline := 0.0
+	for i, val := range Result.Points {
+		Baseline = ThisSport.AdjustedStartingProbability(Result.Spread, val.PlayInfo, Baseline)
+		c.Points[i] = Point{Elapsed: val.Elapsed, HomeWP: val.HomeWP, Baseline: Baseline}
+		if !Clock && len(Result.Points) > 1 {
+			c.Points[i].Elapsed = c.TotalMinutes * float64(i) / float64(len(Result.Points)-1)
+		}
+	}
+	return c
+}
+
+// The number of periods and their length, or the NFL's if PeriodMinutes is left out.
+func (c Chart) periods() (float64, float64) {
+	if c.PeriodMinutes <= 0 {
+		return nflwp.NFL.Periods, nflwp.NFL.PeriodMinutes
+	}
+	return c.Periods, c.PeriodMinutes
+}
+
+// A shaded piece of the area between the curves, from X0 to X1, where the curve is on one side of the baseline.
+type band struct {
+	X0, WP0, Base0 float64
+	X1, WP1, Base1 float64
+	Above          bool
+}
+
+// Split the area between the curves into bands, cutting segments where the curves cross.
+func (c Chart) bands() []band {
+	var Bands []band
+	for i := 0; i+1 < len(c.Points); i++ {
+		a, b := c.Points[i], c.Points[i+1]
+		d0, d1 := a.HomeWP-a.Baseline, b.HomeWP-b.Baseline
+		if d0*d1 < 0 {
+			t := d0 / (d0 - d1)
+			x := a.Elapsed + t*(b.Elapsed-a.Elapsed)
+			y := a.HomeWP + t*(b.HomeWP-a.HomeWP)
+			Bands = append(Bands, band{a.Elapsed, a.HomeWP, a.Baseline, x, y, y, d0 > 0}, band{x, y, y, b.Elapsed, b.HomeWP, b.Baseline, d1 > 0})
+			continue
+		}
+		Bands = append(Bands, band{a.Elapsed, a.HomeWP, a.Baseline, b.Elapsed, b.HomeWP, b.Baseline, d0+d1 > 0})
+	}
+	return Bands
+}
+
+// The layout of a chart of a given size: the plot area and how to map minutes and probabilities into it.
+type layout struct {
+	Left, Top, Width, Height float64
+	Minutes                  float64
+}
+
+// A size that leaves no room inside the margins is an error.
+func newLayout(Width, Height int, Margin float64, Minutes float64) (layout, error) {
+	if float64(Width) <= 2*Margin || float64(Height) <= 2*Margin {
+		return layout{}, fmt.Errorf("a %vx%v chart is too small to plot, it has to be bigger than %vx%v", Width, Height, 2*Margin, 2*Margin)
+	}
+	return layout{Left: Margin, Top: Margin, Width: float64(Width) - 2*Margin, Height: float64(Height) - 2*Margin, Minutes: Minutes}, nil
+}
+
+func (l layout) X(Elapsed float64) float64 {
+	return l.Left + Elapsed/l.Minutes*l.Width
+}
+
+func (l layout) Y(WP float64) float64 {
+	return l.Top + (1-WP)*l.Height
+}
+
+// Write the chart as an SVG of the given size in pixels. It has to be bigger than 100x100.
+func (c Chart) WriteSVG(w io.Writer, Width, Height int) error {
+	l, err := newLayout(Width, Height, 50, c.TotalMinutes)
+	if err != nil {
+		return err
+	}
+	Periods, PeriodMinutes := c.periods()
+	// A bufio.Writer keeps the first error, so Flush returns it.
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" font-family=\"sans-serif\" font-size=\"12\">\n", Width, Height)
+	fmt.Fprintf(bw, "<rect width=\"%v\" height=\"%v\" fill=\"#fff\"/>\n", Width, Height)
+	fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\" font-size=\"14\">%v</text>\n", l.X(c.TotalMinutes/2), l.Top/2, template.HTMLEscapeString(c.Title))
+	for _, Band := range c.bands() {
+		Color := BelowColor
+		if Band.Above {
+			Color = AboveColor
+		}
+		fmt.Fprintf(bw, "<polygon points=\"%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f\" fill=\"%v\" fill-opacity=\"0.3\"/>\n",
+			l.X(Band.X0), l.Y(Band.WP0), l.X(Band.X1), l.Y(Band.WP1), l.X(Band.X1), l.Y(Band.Base1), l.X(Band.X0), l.Y(Band.Base0), Color)
+	}
+	// Period lines and the 50% line.
+	for Minute := PeriodMinutes; Minute < c.TotalMinutes; Minute += PeriodMinutes {
+		fmt.Fprintf(bw, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"#ccc\"/>\n", l.X(Minute), l.Y(1), l.X(Minute), l.Y(0))
+	}
+	fmt.Fprintf(bw, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"#ccc\" stroke-dasharray=\"4\"/>\n", l.X(0), l.Y(0.5), l.X(c.TotalMinutes), l.Y(0.5))
+	fmt.Fprintf(bw, "<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" fill=\"none\" stroke=\"#000\"/>\n", l.Left, l.Top, l.Width, l.Height)
+	for _, val := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"end\">%v%%</text>\n", l.Left-6, l.Y(val)+4, val*100)
+	}
+	for i, Minute := 0, 0.0; Minute < c.TotalMinutes; i, Minute = i+1, Minute+PeriodMinutes {
+		Label := fmt.Sprintf("Q%v", i+1)
+		if float64(i) >= Periods {
+			Label = "OT"
+		}
+		fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\">%v</text>\n", l.X(Minute+PeriodMinutes/2), l.Y(0)+16, Label)
+	}
+	fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"end\">%v</text>\n", l.X(c.TotalMinutes)-4, l.Y(1)+14, template.HTMLEscapeString(c.HomeTeam))
+	fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"end\">%v</text>\n", l.X(c.TotalMinutes)-4, l.Y(0)-6, template.HTMLEscapeString(c.VisitingTeam))
+	fmt.Fprintf(bw, "<polyline points=\"%v\" fill=\"none\" stroke=\"%v\" stroke-width=\"1.5\" stroke-dasharray=\"6 3\"/>\n", c.polyline(l, true), BaselineColor)
+	fmt.Fprintf(bw, "<polyline points=\"%v\" fill=\"none\" stroke=\"%v\" stroke-width=\"2\"/>\n", c.polyline(l, false), WPColor)
+	fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%.1f\">%v WPADJUST %+.4f</text>\n", l.Left, float64(Height)-l.Top/4, template.HTMLEscapeString(c.HomeTeam), c.WPADJUST)
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+func (c Chart) polyline(l layout, Baseline bool) string {
+	Points := ""
+	for i, val := range c.Points {
+		if i > 0 {
+			Points += " "
+		}
+		WP := val.HomeWP
+		if Baseline {
+			WP = val.Baseline
+		}
+		Points += fmt.Sprintf("%.1f,%.1f", l.X(val.Elapsed), l.Y(WP))
+	}
+	return Points
+}
@@ -0,0 +1,175 @@
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/thedadams/nflwp"
+)
+
+func testGame() nflwp.GameResult {
+	return nflwp.GameResult{HomeTeam: "GNB", VisitingTeam: "CHI", Date: "20150913", Spread: -3, HomeWPADJUST: 0.05, Points: []nflwp.WPPoint{
+		{HomeWP: 0.6, PlayInfo: "\"Q1 15:00 CHI 0-GNB 0\"", Quarter: 1, Remaining: 60, TotalMinutes: 60},
+		{HomeWP: 0.3, PlayInfo: "\"Q2 15:00 CHI 7-GNB 0\"", Quarter: 2, Remaining: 45, Elapsed: 15, TotalMinutes: 60},
+		{HomeWP: 0.8, PlayInfo: "\"Q3 15:00 CHI 7-GNB 14\"", Quarter: 3, Remaining: 30, Elapsed: 30, TotalMinutes: 60},
+		{HomeWP: 1, PlayInfo: "\"Q4 0:00 CHI 7-GNB 21\"", Quarter: 4, Elapsed: 60, TotalMinutes: 60},
+	}}
+}
+
+func TestNew(t *testing.T) {
+	game := testGame()
+	c := New(game)
+	if c.TotalMinutes != 60 || len(c.Points) != 4 || c.WPADJUST != 0.05 {
+		t.Fatalf("We got an unexpected result: %+v", c)
+	}
+	baseline := 0.0
+	for i, val := range game.Points {
+		baseline = nflwp.FindAdjustedStartingProbability(game.Spread, val.PlayInfo, baseline)
+		if c.Points[i].Elapsed != val.Elapsed || c.Points[i].HomeWP != val.HomeWP || c.Points[i].Baseline != baseline {
+			t.Errorf("We got an unexpected result: %+v instead of %v, %v, %v", c.Points[i], val.Elapsed, val.HomeWP, baseline)
+		}
+	}
+	// Without a clock the points are spread over the game.
+	for i := range game.Points {
+		game.Points[i].Elapsed = 0
+	}
+	expected := []float64{0, 20, 40, 60}
+	for i, val := range New(game).Points {
+		if val.Elapsed != expected[i] {
+			t.Errorf("We got an unexpected result: %v instead of %v", val.Elapsed, expected[i])
+		}
+	}
+}
+
+func TestNewForSport(t *testing.T) {
+	game := testGame()
+	c := NewForSport(game, nflwp.NCAAF)
+	if c.TotalMinutes != nflwp.NCAAF.GameMinutes() || c.Periods != nflwp.NCAAF.Periods || c.PeriodMinutes != nflwp.NCAAF.PeriodMinutes {
+		t.Errorf("We got an unexpected result: %+v instead of %v minutes", c, nflwp.NCAAF.GameMinutes())
+	}
+	// College margins are wider, so the spread's baseline is closer to a coin flip.
+	expected := nflwp.NCAAF.AdjustedStartingProbability(game.Spread, game.Points[0].PlayInfo, 0)
+	if c.Points[0].Baseline != expected || expected >= New(game).Points[0].Baseline {
+		t.Errorf("We got an unexpected result: %v instead of %v", c.Points[0].Baseline, expected)
+	}
+}
+
+func TestBands(t *testing.T) {
+	c := Chart{TotalMinutes: 60, Points: []Point{{0, 0.6, 0.5}, {30, 0.4, 0.5}, {60, 0.3, 0.5}}}
+	bands := c.bands()
+	if len(bands) != 3 {
+		t.Fatalf("We got an unexpected result: %v bands instead of 3", len(bands))
+	}
+	// The curves cross halfway through the first segment.
+	if !bands[0].Above || bands[1].Above || bands[2].Above || math.Abs(bands[0].X1-15) > 1e-9 || math.Abs(bands[1].WP0-0.5) > 1e-9 {
+		t.Errorf("We got an unexpected result: %+v", bands)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New(testGame()).WriteSVG(&buf, 900, 500); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	for _, val := range []string{"<svg", "</svg>", "CHI at GNB, 20150913", "polyline", AboveColor, BelowColor, "WPADJUST +0.0500", "Q4"} {
+		if !strings.Contains(svg, val) {
+			t.Errorf("We got an unexpected result: the SVG has no %q", val)
+		}
+	}
+	c := New(testGame())
+	c.Title, c.HomeTeam, c.VisitingTeam = "<script>", "A&M", "\"Bears\""
+	buf.Reset()
+	if err := c.WriteSVG(&buf, 900, 500); err != nil {
+		t.Fatal(err)
+	}
+	svg = buf.String()
+	if strings.Contains(svg, "<script>") || !strings.Contains(svg, "&lt;script&gt;") || !strings.Contains(svg, "A&amp;M") || !strings.Contains(svg, "&#34;Bears&#34;") {
+		t.Errorf("We got an unexpected result: the SVG doesn't escape the names: %v", svg)
+	}
+	if err := c.WriteSVG(failingWriter{}, 900, 500); err == nil {
+		t.Errorf("We should get the writer's error from WriteSVG")
+	}
+}
+
+func TestChartPeriods(t *testing.T) {
+	// Two 20 minute halves and 10 minutes of overtime.
+	c := Chart{TotalMinutes: 50, Periods: 2, PeriodMinutes: 20, Points: []Point{{0, 0.5, 0.5}, {50, 1, 0.5}}}
+	var buf bytes.Buffer
+	if err := c.WriteSVG(&buf, 900, 500); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	if strings.Count(svg, "<line") != 2+1 || !strings.Contains(svg, ">Q2<") || strings.Contains(svg, ">Q3<") || !strings.Contains(svg, ">OT<") {
+		t.Errorf("We got an unexpected result: %v", svg)
+	}
+	// A chart without periods has the NFL's quarters.
+	c = Chart{TotalMinutes: 60, Points: c.Points}
+	buf.Reset()
+	if err := c.WriteSVG(&buf, 900, 500); err != nil {
+		t.Fatal(err)
+	}
+	if svg = buf.String(); strings.Count(svg, "<line") != 3+1 || !strings.Contains(svg, ">Q4<") || strings.Contains(svg, ">OT<") {
+		t.Errorf("We got an unexpected result: %v", svg)
+	}
+}
+
+func TestChartTooSmall(t *testing.T) {
+	c := New(testGame())
+	for _, size := range [][2]int{{100, 500}, {900, 100}, {0, 0}, {-5, 500}} {
+		if err := c.WriteSVG(&bytes.Buffer{}, size[0], size[1]); err == nil {
+			t.Errorf("We should not be able to write a %vx%v SVG", size[0], size[1])
+		}
+	}
+	if err := c.WritePNG(&bytes.Buffer{}, 40, 200); err == nil {
+		t.Errorf("We should not be able to write a 40x200 PNG")
+	}
+	if img, err := c.Image(41, 41); err != nil || img.Bounds().Dx() != 41 {
+		t.Errorf("We got an unexpected result: %v", err)
+	}
+}
+
+// A writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("the disk is full")
+}
+
+func TestParseColor(t *testing.T) {
+	colors := []string{"#1f77b4", "1f77b4", "#fff", "", "#12345", "purple"}
+	expectedResults := []color.RGBA{{0x1f, 0x77, 0xb4, 255}, {0x1f, 0x77, 0xb4, 255}, {255, 255, 255, 255}, {0, 0, 0, 255}, {0, 0, 0, 255}, {0, 0, 0, 255}}
+	for i := range colors {
+		if result := parseColor(colors[i], 1); result != expectedResults[i] {
+			t.Errorf("We got an unexpected result for %q: %v instead of %v", colors[i], result, expectedResults[i])
+		}
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New(testGame()).WritePNG(&buf, 300, 200); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 300 || img.Bounds().Dy() != 200 {
+		t.Fatalf("We got an unexpected result: %v instead of 300x200", img.Bounds())
+	}
+	// The corner is outside the plot and the curve starts at 60% on the left edge.
+	if r, g, b, _ := img.At(0, 0).RGBA(); r>>8 != 255 || g>>8 != 255 || b>>8 != 255 {
+		t.Errorf("We got an unexpected result: %v instead of white", img.At(0, 0))
+	}
+	wp := parseColor(WPColor, 1)
+	l, _ := newLayout(300, 200, 20, 60)
+	if r, g, b, _ := img.At(int(l.X(0))+1, int(math.Round(l.Y(0.6)))).RGBA(); uint8(r>>8) != wp.R || uint8(g>>8) != wp.G || uint8(b>>8) != wp.B {
+		t.Errorf("We got an unexpected result: %v instead of %v", img.At(int(l.X(0))+1, int(math.Round(l.Y(0.6)))), wp)
+	}
+}
@@ -0,0 +1,112 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Turn a color like "#1f77b4" or "#1f7" into an RGBA with the given opacity from 0 to 1.
+// A color that can't be read, like "", is black.
+func parseColor(Hex string, Opacity float64) color.RGBA {
+	Hex = strings.TrimPrefix(Hex, "#")
+	if len(Hex) == 3 {
+		Hex = string([]byte{Hex[0], Hex[0], Hex[1], Hex[1], Hex[2], Hex[2]})
+	}
+	val, err := strconv.ParseUint(Hex, 16, 32)
+	if err != nil || len(Hex) != 6 {
+		val = 0
+	}
+	return color.RGBA{R: uint8(val >> 16), G: uint8(val >> 8), B: uint8(val), A: uint8(255 * Opacity)}
+}
+
+// Blend a color over the pixel at x, y.
+func blend(img *image.RGBA, x, y int, c color.RGBA) {
+	if !(image.Point{x, y}.In(img.Rect)) {
+		return
+	}
+	Old := img.RGBAAt(x, y)
+	a := float64(c.A) / 255
+	mix := func(New, Old uint8) uint8 { return uint8(math.Round(a*float64(New) + (1-a)*float64(Old))) }
+	img.SetRGBA(x, y, color.RGBA{mix(c.R, Old.R), mix(c.G, Old.G), mix(c.B, Old.B), 255})
+}
+
+// Draw a line of the given width from (x0, y0) to (x1, y1).
+func drawLine(img *image.RGBA, x0, y0, x1, y1, Width float64, c color.RGBA) {
+	Steps := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0))) + 1
+	Radius := Width / 2
+	for i := 0; i <= Steps; i++ {
+		t := float64(i) / float64(Steps)
+		x, y := x0+t*(x1-x0), y0+t*(y1-y0)
+		for px := int(math.Floor(x - Radius)); px <= int(math.Ceil(x+Radius)); px++ {
+			for py := int(math.Floor(y - Radius)); py <= int(math.Ceil(y+Radius)); py++ {
+				if (float64(px)-x)*(float64(px)-x)+(float64(py)-y)*(float64(py)-y) <= Radius*Radius+0.25 {
+					img.SetRGBA(px, py, c)
+				}
+			}
+		}
+	}
+}
+
+// Draw the chart into an image of the given size, which has to be bigger than 40x40. It is the SVG without the text.
+func (c Chart) Image(Width, Height int) (*image.RGBA, error) {
+	l, err := newLayout(Width, Height, 20, c.TotalMinutes)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	for x := 0; x < Width; x++ {
+		for y := 0; y < Height; y++ {
+			img.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+		}
+	}
+	// Shade each pixel column between the curves.
+	for _, Band := range c.bands() {
+		Color := parseColor(BelowColor, 0.3)
+		if Band.Above {
+			Color = parseColor(AboveColor, 0.3)
+		}
+		Start, End := l.X(Band.X0), l.X(Band.X1)
+		for x := int(math.Round(Start)); x < int(math.Round(End)); x++ {
+			t := (float64(x) + 0.5 - Start) / (End - Start)
+			t = math.Min(math.Max(t, 0), 1)
+			y0, y1 := l.Y(Band.WP0+t*(Band.WP1-Band.WP0)), l.Y(Band.Base0+t*(Band.Base1-Band.Base0))
+			for y := int(math.Round(math.Min(y0, y1))); y < int(math.Round(math.Max(y0, y1))); y++ {
+				blend(img, x, y, Color)
+			}
+		}
+	}
+	Grid := color.RGBA{204, 204, 204, 255}
+	_, PeriodMinutes := c.periods()
+	for Minute := PeriodMinutes; Minute < c.TotalMinutes; Minute += PeriodMinutes {
+		drawLine(img, l.X(Minute), l.Y(1), l.X(Minute), l.Y(0), 1, Grid)
+	}
+	drawLine(img, l.X(0), l.Y(0.5), l.X(c.TotalMinutes), l.Y(0.5), 1, Grid)
+	Black := color.RGBA{0, 0, 0, 255}
+	drawLine(img, l.Left, l.Top, l.Left+l.Width, l.Top, 1, Black)
+	drawLine(img, l.Left, l.Top+l.Height, l.Left+l.Width, l.Top+l.Height, 1, Black)
+	drawLine(img, l.Left, l.Top, l.Left, l.Top+l.Height, 1, Black)
+	drawLine(img, l.Left+l.Width, l.Top, l.Left+l.Width, l.Top+l.Height, 1, Black)
+	for i := 0; i+1 < len(c.Points); i++ {
+		a, b := c.Points[i], c.Points[i+1]
+		drawLine(img, l.X(a.Elapsed), l.Y(a.Baseline), l.X(b.Elapsed), l.Y(b.Baseline), 1.5, parseColor(BaselineColor, 1))
+	}
+	for i := 0; i+1 < len(c.Points); i++ {
+		a, b := c.Points[i], c.Points[i+1]
+		drawLine(img, l.X(a.Elapsed), l.Y(a.HomeWP), l.X(b.Elapsed), l.Y(b.HomeWP), 2, parseColor(WPColor, 1))
+	}
+	return img, nil
+}
+
+// Write the chart as a PNG of the given size in pixels.
+func (c Chart) WritePNG(w io.Writer, Width, Height int) error {
+	img, err := c.Image(Width, Height)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
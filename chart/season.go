@@ -0,0 +1,245 @@
+package chart
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/thedadams/nflwp"
+)
+
+// The metrics the season charts draw, as per-game averages.
+var SeasonMetrics = []int{nflwp.WPADJUST, nflwp.STRAIGHTWPADJUST, nflwp.OPPWPADJUST}
+
+// The colors the season metrics are drawn in.
+var metricColors = map[int]string{nflwp.WPADJUST: "#1f77b4", nflwp.STRAIGHTWPADJUST: "#ff7f0e", nflwp.OPPWPADJUST: "#2ca02c"}
+
+// A TeamRating is a team's per-game averages. OPPWPADJUST is 0 until a team has played two games.
+type TeamRating struct {
+	Rank             int
+	Team             string
+	Games            float64
+	Wins             float64
+	WPADJUST         float64
+	STRAIGHTWPADJUST float64
+	OPPWPADJUST      float64
+}
+
+// Rank the teams that have played by average WPADJUST, best first. "BYE" is left out.
+func Rank(TeamData nflwp.AllTeamData) []TeamRating {
+	var Ratings []TeamRating
+	for _, Team := range TeamData.Teams() {
+		if Team == "BYE" || TeamData[Team][nflwp.GAMESPLAYED] == 0 {
+			continue
+		}
+		Rating := TeamRating{Team: Team, Games: TeamData[Team][nflwp.GAMESPLAYED], Wins: TeamData[Team][nflwp.GAMESWON]}
+		Rating.WPADJUST, _ = TeamData.Average(Team, nflwp.WPADJUST)
+		Rating.STRAIGHTWPADJUST, _ = TeamData.Average(Team, nflwp.STRAIGHTWPADJUST)
+		Rating.OPPWPADJUST, _ = TeamData.Average(Team, nflwp.OPPWPADJUST)
+		Ratings = append(Ratings, Rating)
+	}
+	sort.SliceStable(Ratings, func(i, j int) bool { return Ratings[i].WPADJUST > Ratings[j].WPADJUST })
+	for i := range Ratings {
+		Ratings[i].Rank = i + 1
+	}
+	return Ratings
+}
+
+// The largest absolute per-game average of the metrics over the timeline, so charts of a season share a scale.
+func seasonScale(Timeline nflwp.SeasonTimeline, Teams []string, Metrics ...int) float64 {
+	Scale := 0.0
+	for _, Snapshot := range Timeline {
+		for _, Team := range Teams {
+			for _, Metric := range Metrics {
+				if val, ok := Snapshot.TeamData.Average(Team, Metric); ok {
+					Scale = math.Max(Scale, math.Abs(val))
+				}
+			}
+		}
+	}
+	if Scale == 0 {
+		return 1
+	}
+	return Scale
+}
+
+// Write a team's week by week WPADJUST, STRAIGHTWPADJUST and OPPWPADJUST averages as an SVG line chart of the given size.
+// Weeks where the team doesn't have an average yet are skipped, and a team that hasn't played in the timeline is an error.
+func WriteTeamSVG(w io.Writer, Timeline nflwp.SeasonTimeline, Team string, Width, Height int) error {
+	Teams := Timeline.Teams()
+	if i := sort.SearchStrings(Teams, Team); i == len(Teams) || Teams[i] != Team {
+		return fmt.Errorf("%v hasn't played in the season", Team)
+	}
+	return writeTeamSVG(w, Timeline, Team, Width, Height, seasonScale(Timeline, []string{Team}, SeasonMetrics...))
+}
+
+func writeTeamSVG(w io.Writer, Timeline nflwp.SeasonTimeline, Team string, Width, Height int, Scale float64) error {
+	const Margin = 50.0
+	Left, Top := Margin, Margin
+	PlotWidth, PlotHeight := float64(Width)-2*Margin, float64(Height)-2*Margin
+	X := func(i int) float64 {
+		if len(Timeline) < 2 {
+			return Left + PlotWidth/2
+		}
+		return Left + float64(i)/float64(len(Timeline)-1)*PlotWidth
+	}
+	Y := func(val float64) float64 { return Top + (1-val/Scale)/2*PlotHeight }
+	// A bufio.Writer keeps the first error, so Flush returns it.
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" font-family=\"sans-serif\" font-size=\"12\">\n", Width, Height)
+	fmt.Fprintf(bw, "<rect width=\"%v\" height=\"%v\" fill=\"#fff\"/>\n", Width, Height)
+	fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\" font-size=\"14\">%v</text>\n", Left+PlotWidth/2, Top/2, template.HTMLEscapeString(Team))
+	fmt.Fprintf(bw, "<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" fill=\"none\" stroke=\"#000\"/>\n", Left, Top, PlotWidth, PlotHeight)
+	fmt.Fprintf(bw, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"#ccc\" stroke-dasharray=\"4\"/>\n", Left, Y(0), Left+PlotWidth, Y(0))
+	for _, val := range []float64{-Scale, 0, Scale} {
+		fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"end\">%+.3f</text>\n", Left-6, Y(val)+4, val)
+	}
+	for i, Snapshot := range Timeline {
+		fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\">%v</text>\n", X(i), Top+PlotHeight+16, Snapshot.Week)
+	}
+	fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\">Week</text>\n", Left+PlotWidth/2, Top+PlotHeight+34)
+	for j, Metric := range SeasonMetrics {
+		Color := metricColors[Metric]
+		Points := ""
+		for i, Snapshot := range Timeline {
+			val, ok := Snapshot.TeamData.Average(Team, Metric)
+			if !ok {
+				continue
+			}
+			Points += fmt.Sprintf("%.1f,%.1f ", X(i), Y(val))
+			fmt.Fprintf(bw, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"2.5\" fill=\"%v\"/>\n", X(i), Y(val), Color)
+		}
+		fmt.Fprintf(bw, "<polyline points=\"%v\" fill=\"none\" stroke=\"%v\" stroke-width=\"2\"/>\n", strings.TrimSpace(Points), Color)
+		fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%.1f\" fill=\"%v\">%v</text>\n", Left+6, Top+16+float64(j)*14, Color, nflwp.MetricNames[Metric])
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// The color of a value on a heat map from -Scale to Scale, fading from BelowColor through white to AboveColor.
+func heatColor(val, Scale float64) string {
+	Color := parseColor(AboveColor, 1)
+	if val < 0 {
+		Color = parseColor(BelowColor, 1)
+	}
+	t := math.Min(math.Abs(val)/Scale, 1)
+	fade := func(c uint8) uint8 { return uint8(math.Round(255 - t*(255-float64(c)))) }
+	return fmt.Sprintf("#%02x%02x%02x", fade(Color.R), fade(Color.G), fade(Color.B))
+}
+
+// Write a heat map of every team's per-game average of the metric after each week as an SVG, one row per team ranked
+// by the last week, best first. Cells are empty where a team doesn't have an average yet.
+func WriteHeatMapSVG(w io.Writer, Timeline nflwp.SeasonTimeline, Metric int) error {
+	const Cell, Left, Top = 28.0, 50.0, 50.0
+	Teams := Timeline.Teams()
+	Latest := Timeline.Latest()
+	sort.SliceStable(Teams, func(i, j int) bool {
+		a, _ := Latest.Average(Teams[i], Metric)
+		b, _ := Latest.Average(Teams[j], Metric)
+		return a > b
+	})
+	Scale := seasonScale(Timeline, Teams, Metric)
+	Width, Height := Left+Cell*float64(len(Timeline))+10, Top+Cell*float64(len(Teams))+30
+	// A bufio.Writer keeps the first error, so Flush returns it.
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" font-family=\"sans-serif\" font-size=\"11\">\n", Width, Height)
+	fmt.Fprintf(bw, "<rect width=\"%v\" height=\"%v\" fill=\"#fff\"/>\n", Width, Height)
+	fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\" font-size=\"14\">%v by week</text>\n", Left, Top/3+6, nflwp.MetricNames[Metric])
+	for i, Snapshot := range Timeline {
+		fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\" text-anchor=\"middle\">%v</text>\n", Left+Cell*(float64(i)+0.5), Top-6, Snapshot.Week)
+	}
+	for j, Team := range Teams {
+		y := Top + Cell*float64(j)
+		fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\" text-anchor=\"end\">%v</text>\n", Left-6, y+Cell/2+4, template.HTMLEscapeString(Team))
+		for i, Snapshot := range Timeline {
+			val, ok := Snapshot.TeamData.Average(Team, Metric)
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(bw, "<rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"%v\"><title>%v week %v: %+.4f</title></rect>\n",
+				Left+Cell*float64(i), y, Cell-1, Cell-1, heatColor(val, Scale), template.HTMLEscapeString(Team), Snapshot.Week, val)
+		}
+	}
+	fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\">%+.3f to %+.3f</text>\n", Left, Height-10, -Scale, Scale)
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// Numbers are formatted before they reach the template, which would escape the sign.
+var rankingTemplate = template.Must(template.New("ranking").Funcs(template.FuncMap{
+	"signed": func(val float64) template.HTML { return template.HTML(fmt.Sprintf("%+.4f", val)) },
+}).Parse(`<table class="ranking">
+<thead><tr><th>Rank</th><th>Team</th><th>Games</th><th>Wins</th><th>WPADJUST</th><th>STRAIGHTWPADJUST</th><th>OPPWPADJUST</th></tr></thead>
+<tbody>
+{{range .}}<tr><td>{{.Rank}}</td><td>{{.Team}}</td><td>{{.Games}}</td><td>{{.Wins}}</td><td>{{signed .WPADJUST}}</td><td>{{signed .STRAIGHTWPADJUST}}</td><td>{{signed .OPPWPADJUST}}</td></tr>
+{{end}}</tbody>
+</table>
+`))
+
+// Write the teams ranked by average WPADJUST as an HTML table, see Rank.
+func WriteRankingHTML(w io.Writer, TeamData nflwp.AllTeamData) error {
+	return rankingTemplate.Execute(w, Rank(TeamData))
+}
+
+var seasonTemplate = template.Must(template.New("season").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; }
+.ranking { border-collapse: collapse; }
+.ranking td, .ranking th { padding: 2px 8px; text-align: right; border-bottom: 1px solid #ddd; }
+.teams svg { display: inline-block; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<h2>Rankings</h2>
+{{.Ranking}}
+<h2>By week</h2>
+{{range .HeatMaps}}{{.}}{{end}}
+<h2>Teams</h2>
+<div class="teams">
+{{range .Teams}}{{.}}{{end}}</div>
+</body>
+</html>
+`))
+
+// Write a page with the ranking table after the last week, a heat map for each of SeasonMetrics and
+// a line chart for every team, all on the same scale.
+func WriteSeasonHTML(w io.Writer, Title string, Timeline nflwp.SeasonTimeline) error {
+	var Page struct {
+		Title    string
+		Ranking  template.HTML
+		HeatMaps []template.HTML
+		Teams    []template.HTML
+	}
+	Page.Title = Title
+	var b strings.Builder
+	if err := WriteRankingHTML(&b, Timeline.Latest()); err != nil {
+		return err
+	}
+	Page.Ranking = template.HTML(b.String())
+	for _, Metric := range SeasonMetrics {
+		b.Reset()
+		if err := WriteHeatMapSVG(&b, Timeline, Metric); err != nil {
+			return err
+		}
+		Page.HeatMaps = append(Page.HeatMaps, template.HTML(b.String()))
+	}
+	Teams := Timeline.Teams()
+	Scale := seasonScale(Timeline, Teams, SeasonMetrics...)
+	for _, Team := range Teams {
+		b.Reset()
+		if err := writeTeamSVG(&b, Timeline, Team, 420, 260, Scale); err != nil {
+			return err
+		}
+		Page.Teams = append(Page.Teams, template.HTML(b.String()))
+	}
+	return seasonTemplate.Execute(w, Page)
+}
@@ -0,0 +1,95 @@
+package chart
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/thedadams/nflwp"
+)
+
+func testTimeline() nflwp.SeasonTimeline {
+	builder := nflwp.NewSeasonBuilder(nil)
+	for _, val := range []nflwp.GameResult{
+		{Week: 1, HomeTeam: "NWE", VisitingTeam: "BUF", HomeWPADJUST: 0.1, VisitingWPADJUST: -0.1, FinalWP: 1},
+		{Week: 1, HomeTeam: "NYJ", VisitingTeam: "MIA", HomeWPADJUST: -0.05, VisitingWPADJUST: 0.05},
+		{Week: 2, HomeTeam: "NWE", VisitingTeam: "NYJ", HomeWPADJUST: 0.2, VisitingWPADJUST: -0.2, FinalWP: 1},
+	} {
+		builder.AddGameResult(val)
+	}
+	return builder.Timeline()
+}
+
+func TestRank(t *testing.T) {
+	ratings := Rank(testTimeline().Latest())
+	expected := []string{"NWE", "MIA", "BUF", "NYJ"}
+	if len(ratings) != len(expected) {
+		t.Fatalf("We got an unexpected result: %+v", ratings)
+	}
+	for i, val := range ratings {
+		if val.Team != expected[i] || val.Rank != i+1 {
+			t.Errorf("We got an unexpected result: %v %v instead of %v %v", val.Team, val.Rank, expected[i], i+1)
+		}
+	}
+	if ratings[0].Games != 2 || ratings[0].Wins != 2 || math.Abs(ratings[0].WPADJUST-0.15) > 1e-9 || math.Abs(ratings[0].OPPWPADJUST+0.05) > 1e-9 {
+		t.Errorf("We got an unexpected result: %+v", ratings[0])
+	}
+}
+
+func TestHeatColor(t *testing.T) {
+	var tests = []struct {
+		val, scale float64
+		expected   string
+	}{
+		{0, 1, "#ffffff"},
+		{1, 1, AboveColor},
+		{-2, 1, BelowColor},
+	}
+	for _, test := range tests {
+		if val := heatColor(test.val, test.scale); val != test.expected {
+			t.Errorf("We got an unexpected result: %v instead of %v", val, test.expected)
+		}
+	}
+}
+
+func TestSeasonWriters(t *testing.T) {
+	timeline := testTimeline()
+	var buf bytes.Buffer
+	if err := WriteTeamSVG(&buf, timeline, "NWE", 700, 400); err != nil {
+		t.Fatal(err)
+	}
+	for _, val := range []string{"<svg", "</svg>", "WPADJUST", "STRAIGHTWPADJUST", "OPPWPADJUST", ">NWE<"} {
+		if !strings.Contains(buf.String(), val) {
+			t.Errorf("We got an unexpected result: the team chart has no %q", val)
+		}
+	}
+	buf.Reset()
+	if err := WriteTeamSVG(&buf, timeline, "nwe", 700, 400); err == nil || buf.Len() != 0 {
+		t.Errorf("We should not be able to chart a team that isn't in the season")
+	}
+	if err := WriteHeatMapSVG(&buf, timeline, nflwp.WPADJUST); err != nil {
+		t.Fatal(err)
+	}
+	// NWE leads the heat map, and every team has a cell for both weeks.
+	svg := buf.String()
+	if !strings.Contains(svg, "WPADJUST by week") || strings.Index(svg, ">NWE<") > strings.Index(svg, ">NYJ<") ||
+		strings.Count(svg, "<rect") != 1+8 || !strings.Contains(svg, "<title>NYJ week 2: -0.1250</title>") {
+		t.Errorf("We got an unexpected result: %v", svg)
+	}
+	if err := WriteTeamSVG(failingWriter{}, timeline, "NWE", 700, 400); err == nil {
+		t.Errorf("We should get the writer's error from WriteTeamSVG")
+	}
+	if err := WriteHeatMapSVG(failingWriter{}, timeline, nflwp.WPADJUST); err == nil {
+		t.Errorf("We should get the writer's error from WriteHeatMapSVG")
+	}
+	buf.Reset()
+	if err := WriteSeasonHTML(&buf, "The 2015 season", timeline); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+	if !strings.Contains(page, "<title>The 2015 season</title>") || strings.Count(page, "<svg") != 3+4 ||
+		!strings.Contains(page, "<td>1</td><td>NWE</td><td>2</td><td>2</td><td>+0.1500</td>") {
+		t.Errorf("We got an unexpected result: %v", page)
+	}
+}
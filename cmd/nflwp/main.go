@@ -0,0 +1,186 @@
+// Command nflwp works with the win probability data from the command line.
+//
+// Usage:
+//
+//	nflwp chart [-db nflwp.db] [-o game.svg] [-width 900] [-height 500] <game link or ID>
+//	nflwp season -db nflwp.db [-o season.html] [-team NWE] [-metric WPADJUST] <season>
+//
+// chart draws a game's win probability against the spread's, as an SVG or, if the output ends in ".png", a PNG.
+// The game is loaded from the database if one is given and fetched from pro-football-reference otherwise.
+//
+// season draws the team ratings of every week saved for the season. An output ending in ".html" is a page with the
+// rankings, heat maps and every team's chart; one ending in ".svg" is the team's chart if -team is given and
+// the heat map of -metric otherwise.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thedadams/nflwp"
+	"github.com/thedadams/nflwp/chart"
+	"github.com/thedadams/nflwp/storage"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "chart":
+		err = chartCommand(os.Args[2:])
+	case "season":
+		err = seasonCommand(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Println("Error: ", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: nflwp chart [-db file] [-o file.svg|file.png] [-width px] [-height px] <game link or ID>")
+	fmt.Fprintln(os.Stderr, "       nflwp season -db file [-o file.html|file.svg] [-team code] [-metric name] <season>")
+}
+
+// Given a game link like "/boxscores/201509100nwe.htm" or just its ID "201509100nwe", return the link.
+func gameLink(Game string) string {
+	if strings.Contains(Game, "/") {
+		return Game
+	}
+	return "/boxscores/" + Game + ".htm"
+}
+
+func chartCommand(Args []string) error {
+	Flags := flag.NewFlagSet("chart", flag.ExitOnError)
+	DB := Flags.String("db", "", "the database to load the game from instead of pro-football-reference")
+	Output := Flags.String("o", "", "the file to write, a PNG if it ends in .png (default the game ID with .svg)")
+	Width := Flags.Int("width", 900, "the width of the chart in pixels")
+	Height := Flags.Int("height", 500, "the height of the chart in pixels")
+	Flags.Parse(Args)
+	if Flags.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+	Link := gameLink(Flags.Arg(0))
+
+	var Result *nflwp.GameResult
+	if *DB != "" {
+		Store, err := storage.Open(*DB)
+		if err != nil {
+			return err
+		}
+		defer Store.Close()
+		if Result, err = Store.LoadGame(Link); err != nil {
+			return fmt.Errorf("loading %v: %v", Link, err)
+		}
+	} else if Result = nflwp.GetGameResultForGameLink(Link); Result == nil {
+		return fmt.Errorf("couldn't get the game %v", Link)
+	}
+
+	if *Output == "" {
+		*Output = strings.TrimSuffix(filepath.Base(Link), filepath.Ext(Link)) + ".svg"
+	}
+	file, err := os.Create(*Output)
+	if err != nil {
+		return err
+	}
+	Chart := chart.New(*Result)
+	if strings.EqualFold(filepath.Ext(*Output), ".png") {
+		err = Chart.WritePNG(file, *Width, *Height)
+	} else {
+		err = Chart.WriteSVG(file, *Width, *Height)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func seasonCommand(Args []string) error {
+	Flags := flag.NewFlagSet("season", flag.ExitOnError)
+	DB := Flags.String("db", "", "the database the season was saved to")
+	Output := Flags.String("o", "", "the file to write, HTML or SVG (default the season with .html)")
+	Team := Flags.String("team", "", "the team to chart when writing SVG, in any case")
+	Metric := Flags.String("metric", "WPADJUST", "the metric of the heat map when writing SVG: WPADJUST, STRAIGHTWPADJUST or OPPWPADJUST")
+	Flags.Parse(Args)
+	if Flags.NArg() != 1 || *DB == "" {
+		usage()
+		os.Exit(2)
+	}
+	Season := Flags.Arg(0)
+	MetricIndex := seasonMetric(strings.ToUpper(*Metric))
+	if MetricIndex < 0 {
+		return fmt.Errorf("there is no season metric %v", *Metric)
+	}
+
+	Store, err := storage.Open(*DB)
+	if err != nil {
+		return err
+	}
+	defer Store.Close()
+	Timeline, err := Store.LoadTimeline(Season)
+	if err != nil {
+		return err
+	}
+	if len(Timeline) == 0 {
+		return fmt.Errorf("no weeks have been saved for %v", Season)
+	}
+	if *Team != "" {
+		var ok bool
+		if *Team, ok = seasonTeam(Timeline, *Team); !ok {
+			return fmt.Errorf("%v hasn't played in the %v season", *Team, Season)
+		}
+	}
+
+	if *Output == "" {
+		*Output = Season + ".html"
+	}
+	file, err := os.Create(*Output)
+	if err != nil {
+		return err
+	}
+	switch {
+	case strings.EqualFold(filepath.Ext(*Output), ".svg") && *Team != "":
+		err = chart.WriteTeamSVG(file, Timeline, *Team, 700, 400)
+	case strings.EqualFold(filepath.Ext(*Output), ".svg"):
+		err = chart.WriteHeatMapSVG(file, Timeline, MetricIndex)
+	default:
+		err = chart.WriteSeasonHTML(file, "The "+Season+" season", Timeline)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// The metric with the name if it is one of chart.SeasonMetrics, or -1.
+func seasonMetric(Name string) int {
+	Metric := nflwp.GetMetricFromName(Name)
+	for _, val := range chart.SeasonMetrics {
+		if val == Metric {
+			return Metric
+		}
+	}
+	return -1
+}
+
+// The team in the timeline with the name, ignoring case, and false if there isn't one.
+func seasonTeam(Timeline nflwp.SeasonTimeline, Name string) (string, bool) {
+	for _, Team := range Timeline.Teams() {
+		if strings.EqualFold(Team, Name) {
+			return Team, true
+		}
+	}
+	return Name, false
+}
@@ -0,0 +1,327 @@
+// Package dataset turns seasons of games into labeled rows for training models,
+// one row per game, with the features each team carried into the game.
+package dataset
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thedadams/nflwp"
+)
+
+const (
+	COVER  = iota // 1 if the row's team covered the spread, 0 if it didn't. Pushes are left out.
+	WIN           // 1 if the row's team won, 0 if it lost. Ties are left out.
+	MARGIN        // The row's team's score minus its opponent's
+)
+
+// The names of the labels, in the order of the constants.
+var LabelNames = []string{"cover", "win", "margin"}
+
+// A Feature is a column of the dataset along with what it means.
+type Feature struct {
+	Name        string
+	Description string
+}
+
+// The features every row has, in column order.
+// "team" is the row's team and "opp" its opponent. Ratings are the season averages going into the game,
+// so a team's first game has ratings of zero.
+var Features = []Feature{
+	{"home", "1 if the team is at home, 0 if it is visiting"},
+	{"spread", "The closing spread from the team's point of view; negative means the team was favored"},
+	{"total", "The closing over/under, 0 if the odds file didn't have one"},
+	{"team_wpadjust", "The team's average WPADJUST"},
+	{"opp_wpadjust", "The opponent's average WPADJUST"},
+	{"team_straightwpadjust", "The team's average STRAIGHTWPADJUST"},
+	{"opp_straightwpadjust", "The opponent's average STRAIGHTWPADJUST"},
+	{"team_oppwpadjust", "The team's average OPPWPADJUST, not counting its first game"},
+	{"opp_oppwpadjust", "The opponent's average OPPWPADJUST, not counting its first game"},
+	{"team_games", "Games the team has played this season"},
+	{"opp_games", "Games the opponent has played this season"},
+	{"team_winpct", "The team's winning percentage this season, 0 before its first game"},
+	{"opp_winpct", "The opponent's winning percentage this season, 0 before its first game"},
+	{"team_rest", "Days since the team's last game this season, 0 before its first game"},
+	{"opp_rest", "Days since the opponent's last game this season, 0 before its first game"},
+}
+
+// A Game is a completed game along with the total from the odds file.
+// The embedded GameResult's Spread is the closing spread from the home team's point of view.
+type Game = nflwp.SpreadGame
+
+// A Config picks what goes into the dataset.
+// Sport is the name of the sport or its odds files, like "NFL" or "Football", and is the NFL if it is empty.
+// Seasons run from FirstSeason to LastSeason. Rows are only written once both teams have played MinGames games.
+// With BothSides, each game is written twice, once from each team's point of view; otherwise only the home team's.
+// Each of the Predictors adds a column "pred_<name>" with its spread from the team's point of view,
+// which is NaN until both teams have played the games PredictorMinGames asks for.
+// Aggregator is how the ratings are averaged over the season, CumulativeMean if it is nil.
+type Config struct {
+	Sport       string
+	FirstSeason int
+	LastSeason  int
+	Label       int
+	MinGames    float64
+	BothSides   bool
+	Predictors  []nflwp.Predictor
+	Aggregator  nflwp.Aggregator
+}
+
+// A Row is one team's side of a game.
+type Row struct {
+	Season   string
+	Week     int
+	Date     string
+	Team     string
+	Opponent string
+	Features []float64
+	Label    float64
+}
+
+// A Dataset is the rows along with the names of their features, in column order.
+type Dataset struct {
+	Label    int
+	Features []string
+	Rows     []Row
+}
+
+// The names of the feature columns for the config.
+// Two predictors that would get the same column are an error.
+func (c Config) FeatureNames() ([]string, error) {
+	Names := make([]string, 0, len(Features)+len(c.Predictors))
+	for _, val := range Features {
+		Names = append(Names, val.Name)
+	}
+	Seen := make(map[string]bool)
+	for _, val := range c.Predictors {
+		Name := "pred_" + strings.ToLower(val.Name())
+		if Seen[Name] {
+			return nil, fmt.Errorf("more than one predictor is named %v", val.Name())
+		}
+		Seen[Name] = true
+		Names = append(Names, Name)
+	}
+	return Names, nil
+}
+
+// The sport of the config, and false if it isn't one we know.
+func (c Config) sport() (nflwp.Sport, bool) {
+	if c.Sport == "" {
+		return nflwp.NFL, true
+	}
+	return nflwp.GetSport(c.Sport)
+}
+
+// An error if the config's Label isn't one of COVER, WIN or MARGIN.
+func (c Config) checkLabel() error {
+	if c.Label < 0 || c.Label >= len(LabelNames) {
+		return fmt.Errorf("we don't know the label %v", c.Label)
+	}
+	return nil
+}
+
+// Given the config, read each season's odds file with Sport.LoadGamesFromSpreadFile and build the dataset.
+// Seasons whose file can't be read, sports we don't know, labels we don't know and predictors with the same name are an error.
+func Build(c Config) (*Dataset, error) {
+	var Games []Game
+	ThisSport, ok := c.sport()
+	if !ok {
+		return nil, fmt.Errorf("we don't know the sport %v", c.Sport)
+	}
+	if err := c.checkLabel(); err != nil {
+		return nil, err
+	}
+	if _, err := c.FeatureNames(); err != nil {
+		return nil, err
+	}
+	for Year := c.FirstSeason; Year <= c.LastSeason; Year++ {
+		Season, err := ThisSport.LoadGamesFromSpreadFile(Year)
+		if err != nil {
+			return nil, err
+		}
+		Games = append(Games, Season...)
+	}
+	return BuildFromGames(c, Games)
+}
+
+// Given games in the order they were played, build the dataset.
+// Each season starts over from nothing, and games outside the config's seasons are skipped
+// unless FirstSeason and LastSeason are both zero.
+// A game without a week gets one counted in seven day blocks from the first game of its season.
+// Seasons are kept the way the config's sport keeps them, the NFL's if it isn't one we know,
+// and the predictors' spreads come from that sport's MarginStdDev.
+// A label we don't know and predictors with the same name are an error.
+func BuildFromGames(c Config, Games []Game) (*Dataset, error) {
+	if err := c.checkLabel(); err != nil {
+		return nil, err
+	}
+	Names, err := c.FeatureNames()
+	if err != nil {
+		return nil, err
+	}
+	ThisSport, ok := c.sport()
+	if !ok {
+		ThisSport = nflwp.NFL
+	}
+	Data := &Dataset{Label: c.Label, Features: Names}
+	var Builder *nflwp.SeasonBuilder
+	var Season string
+	var FirstDate time.Time
+	LastPlayed := make(map[string]time.Time)
+	for _, Game := range Games {
+		if c.FirstSeason != 0 || c.LastSeason != 0 {
+			Year, err := strconv.Atoi(Game.Season)
+			if err != nil || Year < c.FirstSeason || Year > c.LastSeason {
+				continue
+			}
+		}
+		Date, _ := time.Parse("20060102", Game.Date)
+		if Builder == nil || Game.Season != Season {
+			Builder = nflwp.NewSeasonBuilderForSport(c.Aggregator, ThisSport)
+			Season = Game.Season
+			FirstDate = Date
+			LastPlayed = make(map[string]time.Time)
+		}
+		if Game.Week == 0 && !Date.IsZero() {
+			Game.Week = nflwp.SeasonWeek(FirstDate, Date)
+		}
+		// Only the two teams matter, with this game's line in SPREAD where the predictors look for it.
+		TeamData := Builder.TeamData.ForGameForSport(nflwp.UpcomingGame{HomeTeam: Game.HomeTeam, VisitingTeam: Game.VisitingTeam, Spread: Game.Spread}, ThisSport)
+		if games(TeamData, Game.HomeTeam) >= c.MinGames && games(TeamData, Game.VisitingTeam) >= c.MinGames {
+			if Row, ok := c.row(TeamData, Game, Date, LastPlayed, true, ThisSport); ok {
+				Data.Rows = append(Data.Rows, Row)
+			}
+			if c.BothSides {
+				if Row, ok := c.row(TeamData, Game, Date, LastPlayed, false, ThisSport); ok {
+					Data.Rows = append(Data.Rows, Row)
+				}
+			}
+		}
+		Builder.AddGameResult(Game.GameResult)
+		LastPlayed[Game.HomeTeam] = Date
+		LastPlayed[Game.VisitingTeam] = Date
+	}
+	return Data, nil
+}
+
+// The row for one side of the game, and false if the label is left out.
+func (c Config) row(TeamData nflwp.AllTeamData, Game Game, Date time.Time, LastPlayed map[string]time.Time, Home bool, ThisSport nflwp.Sport) (Row, bool) {
+	Team, Opponent := Game.HomeTeam, Game.VisitingTeam
+	Spread, Margin, Sign := Game.Spread, Game.HomeScore-Game.VisitingScore, 1.0
+	IsHome := 1.0
+	if !Home {
+		Team, Opponent = Opponent, Team
+		Spread, Margin, Sign = -Spread, -Margin, -1.0
+		IsHome = 0
+	}
+	ThisRow := Row{Season: Game.Season, Week: Game.Week, Date: Game.Date, Team: Team, Opponent: Opponent}
+	switch c.Label {
+	case COVER:
+		if Margin+Spread == 0 {
+			return ThisRow, false
+		}
+		ThisRow.Label = boolToFloat(Margin+Spread > 0)
+	case WIN:
+		if Margin == 0 {
+			return ThisRow, false
+		}
+		ThisRow.Label = boolToFloat(Margin > 0)
+	case MARGIN:
+		ThisRow.Label = Margin
+	}
+	ThisRow.Features = []float64{
+		IsHome,
+		Spread,
+		Game.Total,
+		average(TeamData, Team, nflwp.WPADJUST, 0),
+		average(TeamData, Opponent, nflwp.WPADJUST, 0),
+		average(TeamData, Team, nflwp.STRAIGHTWPADJUST, 0),
+		average(TeamData, Opponent, nflwp.STRAIGHTWPADJUST, 0),
+		average(TeamData, Team, nflwp.OPPWPADJUST, 1),
+		average(TeamData, Opponent, nflwp.OPPWPADJUST, 1),
+		games(TeamData, Team),
+		games(TeamData, Opponent),
+		average(TeamData, Team, nflwp.GAMESWON, 0),
+		average(TeamData, Opponent, nflwp.GAMESWON, 0),
+		rest(LastPlayed, Team, Date),
+		rest(LastPlayed, Opponent, Date),
+	}
+	for _, Guess := range c.Predictors {
+		Min := nflwp.PredictorMinGames(Guess)
+		if games(TeamData, Team) < Min || games(TeamData, Opponent) < Min {
+			ThisRow.Features = append(ThisRow.Features, math.NaN())
+			continue
+		}
+		ThisRow.Features = append(ThisRow.Features, Sign*nflwp.PredictorEstimate(Guess, TeamData, Game.HomeTeam, Game.VisitingTeam, ThisSport).Spread)
+	}
+	return ThisRow, true
+}
+
+// The seasons in the dataset, in order.
+func (d *Dataset) Seasons() []string {
+	Seen := make(map[string]bool)
+	var Seasons []string
+	for _, val := range d.Rows {
+		if !Seen[val.Season] {
+			Seen[val.Season] = true
+			Seasons = append(Seasons, val.Season)
+		}
+	}
+	sort.Strings(Seasons)
+	return Seasons
+}
+
+// Split the dataset by season: rows from the given seasons go to Validation and everything else to Train.
+func (d *Dataset) SplitBySeason(ValidationSeasons ...string) (*Dataset, *Dataset) {
+	Train := &Dataset{Label: d.Label, Features: d.Features}
+	Validation := &Dataset{Label: d.Label, Features: d.Features}
+	Validate := make(map[string]bool)
+	for _, val := range ValidationSeasons {
+		Validate[val] = true
+	}
+	for _, val := range d.Rows {
+		if Validate[val.Season] {
+			Validation.Rows = append(Validation.Rows, val)
+		} else {
+			Train.Rows = append(Train.Rows, val)
+		}
+	}
+	return Train, Validation
+}
+
+// Games the team has played in the season so far.
+func games(TeamData nflwp.AllTeamData, Team string) float64 {
+	if val, ok := TeamData[Team]; ok {
+		return val[nflwp.GAMESPLAYED]
+	}
+	return 0
+}
+
+// The team's per-game average of the metric. Skip is how many games don't count toward it.
+func average(TeamData nflwp.AllTeamData, Team string, Metric int, Skip float64) float64 {
+	Games := games(TeamData, Team) - Skip
+	if Games <= 0 {
+		return 0
+	}
+	return TeamData[Team][Metric] / Games
+}
+
+// Days since the team last played, or 0 if it hasn't.
+func rest(LastPlayed map[string]time.Time, Team string, Date time.Time) float64 {
+	Last, ok := LastPlayed[Team]
+	if !ok || Date.IsZero() {
+		return 0
+	}
+	return Date.Sub(Last).Hours() / 24
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,214 @@
+package dataset
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/thedadams/nflwp"
+)
+
+func testGame(Season, Date, HomeTeam, VisitingTeam string, Spread, HomeScore, VisitingScore, HomeAdjust float64) Game {
+	Result := nflwp.GameResult{Season: Season, Date: Date, HomeTeam: HomeTeam, VisitingTeam: VisitingTeam, Spread: Spread, HomeScore: HomeScore, VisitingScore: VisitingScore}
+	Result.HomeWPADJUST = HomeAdjust
+	Result.VisitingWPADJUST = -HomeAdjust
+	Result.HomeSTRAIGHTWPADJUST = 0.5 + HomeAdjust
+	Result.VisitingSTRAIGHTWPADJUST = 0.5 - HomeAdjust
+	if HomeScore > VisitingScore {
+		Result.FinalWP = 1
+	}
+	return Game{GameResult: Result, Total: 44}
+}
+
+func testGames() []Game {
+	return []Game{
+		testGame("2015", "20150910", "NWE", "PIT", -7, 31, 21, 0.1),
+		testGame("2015", "20150913", "BUF", "IND", 1, 27, 14, 0.2),
+		testGame("2015", "20150920", "BUF", "NWE", 1, 32, 40, -0.05),
+		testGame("2016", "20160911", "NWE", "BUF", 0, 20, 20, 0),
+	}
+}
+
+func TestBuildFromGames(t *testing.T) {
+	data, err := BuildFromGames(Config{Label: COVER}, testGames())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The game in 2016 is a push, so it is left out.
+	if len(data.Rows) != 3 {
+		t.Fatalf("We got an unexpected result: %v instead of %v", len(data.Rows), 3)
+	}
+	row := data.Rows[2]
+	expectedFeatures := []float64{1, 1, 44, 0.2, 0.1, 0.7, 0.6, 0, 0, 1, 1, 1, 1, 7, 10}
+	for i := 0; i < len(expectedFeatures); i++ {
+		if math.Abs(row.Features[i]-expectedFeatures[i]) > 1e-9 {
+			t.Errorf("We got an unexpected result for %v: %v instead of %v", data.Features[i], row.Features[i], expectedFeatures[i])
+		}
+	}
+	if row.Week != 2 || row.Label != 0 {
+		t.Errorf("We got an unexpected result: week %v label %v instead of 2 and 0", row.Week, row.Label)
+	}
+	if _, err := Build(Config{Sport: "Curling"}); err == nil {
+		t.Errorf("We got an unexpected result: no error for a sport we don't know")
+	}
+	if _, err := BuildFromGames(Config{Label: 3}, testGames()); err == nil {
+		t.Errorf("We got an unexpected result: no error for a label we don't know")
+	}
+	if data, _ = BuildFromGames(Config{Sport: "NCAAF", Label: COVER}, testGames()); len(data.Rows) != 3 {
+		t.Errorf("We got an unexpected result: %v instead of %v", len(data.Rows), 3)
+	}
+}
+
+func TestBuildFromGamesBothSidesAndLabels(t *testing.T) {
+	data, err := BuildFromGames(Config{Label: MARGIN, BothSides: true, FirstSeason: 2015, LastSeason: 2015, MinGames: 1}, testGames())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the third game has two teams that have played before.
+	if len(data.Rows) != 2 {
+		t.Fatalf("We got an unexpected result: %v instead of %v", len(data.Rows), 2)
+	}
+	if data.Rows[0].Team != "BUF" || data.Rows[0].Label != -8 || data.Rows[1].Team != "NWE" || data.Rows[1].Label != 8 {
+		t.Errorf("We got an unexpected result: %v", data.Rows)
+	}
+	if data.Rows[1].Features[0] != 0 || data.Rows[1].Features[1] != -1 || data.Rows[1].Features[3] != 0.1 {
+		t.Errorf("We got an unexpected result: %v", data.Rows[1].Features)
+	}
+	data, _ = BuildFromGames(Config{Label: WIN, Predictors: []nflwp.Predictor{nflwp.GuessWPPredictor{}}}, testGames())
+	if len(data.Rows) != 3 || data.Features[len(data.Features)-1] != "pred_guesswp" {
+		t.Fatalf("We got an unexpected result: %v rows, features %v", len(data.Rows), data.Features)
+	}
+	if !math.IsNaN(data.Rows[0].Features[len(Features)]) {
+		t.Errorf("We got an unexpected result: %v instead of NaN", data.Rows[0].Features[len(Features)])
+	}
+}
+
+func TestBuildFromGamesPredictorLine(t *testing.T) {
+	games := append(testGames()[:3], testGame("2015", "20150927", "NWE", "BUF", -3, 24, 17, 0.1))
+	data, err := BuildFromGames(Config{Label: MARGIN, MinGames: 2, Predictors: []nflwp.Predictor{nflwp.EstSpreadPredictor{}}}, games)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Rows) != 1 {
+		t.Fatalf("We got an unexpected result: %v instead of %v", len(data.Rows), 1)
+	}
+	// EstSpread starts from the line, so it has to see -3 and not whatever SPREAD held before the game.
+	TeamData := nflwp.AllTeamDataFromGameResults([]nflwp.GameResult{games[0].GameResult, games[1].GameResult, games[2].GameResult}, nil)
+	TeamData["NWE"][nflwp.SPREAD] = -3
+	TeamData["BUF"][nflwp.SPREAD] = 3
+	expected := nflwp.EstSpreadPredictor{}.Predict(TeamData, "NWE", "BUF").Spread
+	if result := data.Rows[0].Features[len(Features)]; math.Abs(result-expected) > 1e-9 {
+		t.Errorf("We got an unexpected result: %v instead of %v", result, expected)
+	}
+	// College margins are wider, so the same ratings move the line further.
+	data, err = BuildFromGames(Config{Sport: "NCAAF", Label: MARGIN, MinGames: 2, Predictors: []nflwp.Predictor{nflwp.EstSpreadPredictor{}}}, games)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = nflwp.EstSpreadPredictor{}.PredictForSport(TeamData, "NWE", "BUF", nflwp.NCAAF).Spread
+	if result := data.Rows[0].Features[len(Features)]; math.Abs(result-expected) > 1e-9 {
+		t.Errorf("We got an unexpected result: %v instead of %v", result, expected)
+	}
+}
+
+func TestDuplicatePredictorNames(t *testing.T) {
+	c := Config{Label: COVER, Predictors: []nflwp.Predictor{nflwp.GuessWPPredictor{}, nflwp.EstSpreadPredictor{}, nflwp.GuessWPPredictor{}}}
+	if _, err := c.FeatureNames(); err == nil {
+		t.Errorf("We got an unexpected result: no error for two predictors named GuessWP")
+	}
+	if _, err := BuildFromGames(c, testGames()); err == nil {
+		t.Errorf("We got an unexpected result: no error for two predictors named GuessWP")
+	}
+	if _, err := Build(c); err == nil {
+		t.Errorf("We got an unexpected result: no error for two predictors named GuessWP")
+	}
+	c.Predictors = c.Predictors[:2]
+	if names, err := c.FeatureNames(); err != nil || len(names) != len(Features)+2 {
+		t.Errorf("We got an unexpected result: %v %v", names, err)
+	}
+}
+
+func TestSplitBySeason(t *testing.T) {
+	data, err := BuildFromGames(Config{Label: MARGIN}, testGames())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seasons := data.Seasons(); len(seasons) != 2 || seasons[0] != "2015" || seasons[1] != "2016" {
+		t.Errorf("We got an unexpected result: %v", seasons)
+	}
+	train, validation := data.SplitBySeason("2016")
+	if len(train.Rows) != 3 || len(validation.Rows) != 1 || validation.Rows[0].Season != "2016" {
+		t.Errorf("We got an unexpected result: %v and %v rows", len(train.Rows), len(validation.Rows))
+	}
+}
+
+func TestWriters(t *testing.T) {
+	data, err := BuildFromGames(Config{Label: COVER}, testGames()[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := data.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "season,week,date,team,opponent,home,spread") || !strings.HasSuffix(lines[0], ",cover") {
+		t.Errorf("We got an unexpected result: %v", lines)
+	}
+	buf.Reset()
+	if err := data.WriteLIBSVM(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "1 1:1 2:-7 3:44\n1 1:1 2:1 3:44\n"; buf.String() != expected {
+		t.Errorf("We got an unexpected result: %q instead of %q", buf.String(), expected)
+	}
+	// The second write is the first feature.
+	if err := data.WriteLIBSVM(&flakyWriter{fail: 2}); err == nil {
+		t.Errorf("We expected an error from a writer that stops working")
+	}
+	buf.Reset()
+	if err := data.WriteFeatureList(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "14,team_rest,") {
+		t.Errorf("We got an unexpected result: %v", buf.String())
+	}
+	buf.Reset()
+	if err := data.WriteParquet(&buf); err != nil {
+		t.Fatal(err)
+	}
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.NumRows() != 2 || len(file.Schema().Columns()) != len(idColumns)+len(Features)+1 {
+		t.Errorf("We got an unexpected result: %v rows and %v columns", file.NumRows(), len(file.Schema().Columns()))
+	}
+	for i, val := range file.Schema().Columns() {
+		if expected := data.columns()[i]; val[0] != expected {
+			t.Errorf("We got an unexpected result: column %v is %v instead of %v", i, val[0], expected)
+		}
+	}
+	rows := make([]parquet.Row, 1)
+	if n, _ := parquet.NewReader(file).ReadRows(rows); n != 1 {
+		t.Fatalf("We got an unexpected result: %v rows instead of %v", n, 1)
+	}
+	if rows[0][1].Int64() != int64(data.Rows[0].Week) || rows[0][3].String() != data.Rows[0].Team || rows[0][len(idColumns)+1].Double() != data.Rows[0].Features[1] {
+		t.Errorf("We got an unexpected result: %v instead of %+v", rows[0], data.Rows[0])
+	}
+}
+
+// A writer whose write number fail, counting from 1, is the only one that fails.
+type flakyWriter struct {
+	writes, fail int
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.writes++; w.writes == w.fail {
+		return 0, errors.New("the write failed")
+	}
+	return len(p), nil
+}
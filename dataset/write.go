@@ -0,0 +1,132 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// The columns written before the features that say which game a row is.
+var idColumns = []string{"season", "week", "date", "team", "opponent"}
+
+// Write the dataset as CSV with a header row: the id columns, the features and then the label.
+// Missing features are written as NaN.
+func (d *Dataset) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(d.columns())
+	for _, val := range d.Rows {
+		Record := []string{val.Season, strconv.Itoa(val.Week), val.Date, val.Team, val.Opponent}
+		for _, Feature := range val.Features {
+			Record = append(Record, strconv.FormatFloat(Feature, 'g', -1, 64))
+		}
+		cw.Write(append(Record, strconv.FormatFloat(val.Label, 'g', -1, 64)))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Write the dataset in LIBSVM format, "label index:value ...", with features numbered from 1.
+// LIBSVM files have no header, so zero and missing features are left out and WriteFeatureList
+// writes the names that go with the numbers.
+func (d *Dataset) WriteLIBSVM(w io.Writer) error {
+	for _, val := range d.Rows {
+		if _, err := io.WriteString(w, strconv.FormatFloat(val.Label, 'g', -1, 64)); err != nil {
+			return err
+		}
+		for i, Feature := range val.Features {
+			if Feature == 0 || math.IsNaN(Feature) {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, " %v:%v", i+1, strconv.FormatFloat(Feature, 'g', -1, 64)); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write the number, name and description of each feature as CSV with a header row.
+// The numbers are the ones WriteLIBSVM uses. Predictor columns don't have a description.
+func (d *Dataset) WriteFeatureList(w io.Writer) error {
+	Descriptions := make(map[string]string, len(Features))
+	for _, val := range Features {
+		Descriptions[val.Name] = val.Description
+	}
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"index", "name", "description"})
+	for i, val := range d.Features {
+		cw.Write([]string{strconv.Itoa(i + 1), val, Descriptions[val]})
+	}
+	cw.Write([]string{"label", LabelNames[d.Label], ""})
+	cw.Flush()
+	return cw.Error()
+}
+
+// A parquet group whose fields come in the given order rather than alphabetically.
+type orderedGroup struct {
+	parquet.Group
+	order []string
+}
+
+func (g orderedGroup) Fields() []parquet.Field {
+	Fields := make([]parquet.Field, 0, len(g.order))
+	for _, Name := range g.order {
+		for _, val := range g.Group.Fields() {
+			if val.Name() == Name {
+				Fields = append(Fields, val)
+			}
+		}
+	}
+	return Fields
+}
+
+// The names of the columns in order: the id columns, the features and then the label.
+func (d *Dataset) columns() []string {
+	return append(append(append([]string(nil), idColumns...), d.Features...), LabelNames[d.Label])
+}
+
+// The parquet schema of the dataset: the id columns as strings and week, the features and the label as numbers.
+// The columns are in the same order as WriteCSV's.
+func (d *Dataset) ParquetSchema() *parquet.Schema {
+	Columns := parquet.Group{
+		"season":   parquet.String(),
+		"week":     parquet.Leaf(parquet.Int64Type),
+		"date":     parquet.String(),
+		"team":     parquet.String(),
+		"opponent": parquet.String(),
+	}
+	for _, val := range d.Features {
+		Columns[val] = parquet.Leaf(parquet.DoubleType)
+	}
+	Columns[LabelNames[d.Label]] = parquet.Leaf(parquet.DoubleType)
+	return parquet.NewSchema("dataset", orderedGroup{Group: Columns, order: d.columns()})
+}
+
+// Write the dataset as a parquet file with the columns of ParquetSchema.
+func (d *Dataset) WriteParquet(w io.Writer) error {
+	pw := parquet.NewWriter(w, d.ParquetSchema())
+	for _, val := range d.Rows {
+		Record := map[string]any{
+			"season":   val.Season,
+			"week":     int64(val.Week),
+			"date":     val.Date,
+			"team":     val.Team,
+			"opponent": val.Opponent,
+		}
+		for i, Feature := range val.Features {
+			Record[d.Features[i]] = Feature
+		}
+		Record[LabelNames[d.Label]] = val.Label
+		if err := pw.Write(Record); err != nil {
+			return err
+		}
+	}
+	return pw.Close()
+}
@@ -0,0 +1,144 @@
+package nflwp
+
+import (
+	"math"
+)
+
+const (
+	ELOSTART    = 1500.0 // Rating of a team we haven't seen before
+	ELOPERPOINT = 25.0   // Elo points per point of spread
+)
+
+// Elo keeps a power rating for every team.
+// K is how far a single game moves a rating, HomeField is the home team's advantage in Elo points
+// and Regression is the fraction of the way back to ELOSTART each rating moves between seasons.
+// If UseWinProbability is set, the expected score is the win probability of the Elo spread from
+// WinProbability instead of the usual logistic curve.
+type Elo struct {
+	Ratings           map[string]float64
+	K                 float64
+	HomeField         float64
+	Regression        float64
+	UseWinProbability bool
+}
+
+// Returns an Elo with the settings FiveThirtyEight uses for the NFL.
+func NewElo() *Elo {
+	return &Elo{Ratings: make(map[string]float64), K: 20, HomeField: 65, Regression: 1.0 / 3.0, UseWinProbability: true}
+}
+
+// Given a difference in Elo ratings, return the spread. Like everywhere else, a favorite has a negative spread.
+func EloToSpread(Difference float64) float64 {
+	return -Difference / ELOPERPOINT
+}
+
+// Given a spread, return the difference in Elo ratings.
+func SpreadToElo(Spread float64) float64 {
+	return -Spread * ELOPERPOINT
+}
+
+// The team's rating, or ELOSTART if we haven't seen them.
+func (e *Elo) Rating(Team string) float64 {
+	if val, ok := e.Ratings[Team]; ok {
+		return val
+	}
+	return ELOSTART
+}
+
+// The home team's rating advantage, including home field.
+func (e *Elo) difference(HomeTeam, VisitingTeam string) float64 {
+	return e.Rating(HomeTeam) + e.HomeField - e.Rating(VisitingTeam)
+}
+
+// The spread for the home team implied by the ratings.
+func (e *Elo) Spread(HomeTeam, VisitingTeam string) float64 {
+	return EloToSpread(e.difference(HomeTeam, VisitingTeam))
+}
+
+// The expected score for the home team, which is its win probability.
+func (e *Elo) Expected(HomeTeam, VisitingTeam string) float64 {
+	if e.UseWinProbability {
+		return WinProbability(0, e.Spread(HomeTeam, VisitingTeam), STDDEV)
+	}
+	return 1 / (1 + math.Pow(10, -e.difference(HomeTeam, VisitingTeam)/400))
+}
+
+// Blowouts count more than close games, but less so when the favorite wins big.
+func marginMultiplier(Margin, WinnerDifference float64) float64 {
+	return math.Log(math.Abs(Margin)+1) * 2.2 / (WinnerDifference*0.001 + 2.2)
+}
+
+// Update the ratings with the final score of a game. Ties count as half a win for each team.
+func (e *Elo) Update(HomeTeam, VisitingTeam string, HomeScore, VisitingScore float64) {
+	Actual := 0.5
+	WinnerDifference := 0.0
+	if HomeScore > VisitingScore {
+		Actual = 1
+		WinnerDifference = e.difference(HomeTeam, VisitingTeam)
+	} else if HomeScore < VisitingScore {
+		Actual = 0
+		WinnerDifference = -e.difference(HomeTeam, VisitingTeam)
+	}
+	Multiplier := 1.0
+	if HomeScore != VisitingScore {
+		Multiplier = marginMultiplier(HomeScore-VisitingScore, WinnerDifference)
+	}
+	Shift := e.K * Multiplier * (Actual - e.Expected(HomeTeam, VisitingTeam))
+	e.Ratings[HomeTeam] = e.Rating(HomeTeam) + Shift
+	e.Ratings[VisitingTeam] = e.Rating(VisitingTeam) - Shift
+}
+
+// Move every rating Regression of the way back to ELOSTART. Call this between seasons.
+func (e *Elo) NewSeason() {
+	for key, val := range e.Ratings {
+		e.Ratings[key] = val + e.Regression*(ELOSTART-val)
+	}
+}
+
+// Seed the ratings so the spreads they imply match the given lines as closely as possible.
+// Each pass moves both teams in every game halfway toward the line.
+func (e *Elo) SeedFromSpreads(Games []UpcomingGame, Passes int) {
+	for count := 0; count < Passes; count++ {
+		for _, val := range Games {
+			Miss := SpreadToElo(val.Spread) - e.difference(val.HomeTeam, val.VisitingTeam)
+			e.Ratings[val.HomeTeam] = e.Rating(val.HomeTeam) + Miss/4
+			e.Ratings[val.VisitingTeam] = e.Rating(val.VisitingTeam) - Miss/4
+		}
+	}
+}
+
+// Given a link in the format "/boxscore/YYYYMMDD0aaa.htm", update the ratings with the game's final score.
+// This reads the same cached page as GetDataForGameLink. Returns false if the score cannot be found.
+func (e *Elo) UpdateFromGameLink(Link string) bool {
+	body := NFL.fetch(Link)
+	if body == nil {
+		return false
+	}
+	VisitingTeam, HomeTeam := GetTeamNames(string(body))
+	if VisitingTeam == "" {
+		return false
+	}
+	VisitingScore, HomeScore, ok := GetFinalScoreFromProFootballPage(body, VisitingTeam, HomeTeam)
+	if !ok {
+		return false
+	}
+	e.Update(HomeTeam, VisitingTeam, HomeScore, VisitingScore)
+	return true
+}
+
+// EloPredictor predicts the spread from the Elo ratings alone.
+type EloPredictor struct {
+	Elo *Elo
+}
+
+func (EloPredictor) Name() string {
+	return "Elo"
+}
+
+func (EloPredictor) MinGames() float64 {
+	return 0
+}
+
+func (p EloPredictor) Predict(TeamData AllTeamData, HomeTeam, VisitingTeam string) SpreadEstimate {
+	return SpreadEstimate{Spread: p.Elo.Spread(HomeTeam, VisitingTeam), WinProbability: p.Elo.Expected(HomeTeam, VisitingTeam)}
+}
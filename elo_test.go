@@ -0,0 +1,49 @@
+package nflwp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEloUpdate(t *testing.T) {
+	e := NewElo()
+	e.HomeField = 0
+	if math.Abs(e.Expected("NWE", "BUF")-0.5) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", e.Expected("NWE", "BUF"), 0.5)
+	}
+	e.Update("NWE", "BUF", 24, 17)
+	// An even game with a 7 point margin moves the ratings by K*ln(8)/2.
+	expected := ELOSTART + 20*math.Log(8)/2
+	if math.Abs(e.Rating("NWE")-expected) > 0.0005 || math.Abs(e.Rating("NWE")+e.Rating("BUF")-2*ELOSTART) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v and %v instead of %v", e.Rating("NWE"), e.Rating("BUF"), expected)
+	}
+	e.NewSeason()
+	expected = ELOSTART + 20*math.Log(8)/2*2/3
+	if math.Abs(e.Rating("NWE")-expected) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", e.Rating("NWE"), expected)
+	}
+}
+
+func TestEloSpread(t *testing.T) {
+	differences := []float64{0, 75, -175}
+	expectedResults := []float64{0, -3, 7}
+	for i := 0; i < len(differences); i++ {
+		result := EloToSpread(differences[i])
+		if math.Abs(result-expectedResults[i]) > 0.0005 || math.Abs(SpreadToElo(result)-differences[i]) > 0.0005 {
+			t.Errorf("We got an unexpected result: %v instead of %v", result, expectedResults[i])
+		}
+	}
+	e := NewElo()
+	e.SeedFromSpreads([]UpcomingGame{{HomeTeam: "NWE", VisitingTeam: "BUF", Spread: -7}, {HomeTeam: "NYJ", VisitingTeam: "MIA", Spread: 3}}, 50)
+	if math.Abs(e.Spread("NWE", "BUF")+7) > 0.05 || math.Abs(e.Spread("NYJ", "MIA")-3) > 0.05 {
+		t.Errorf("We got an unexpected result: %v and %v instead of %v and %v", e.Spread("NWE", "BUF"), e.Spread("NYJ", "MIA"), -7, 3)
+	}
+}
+
+func TestGetFinalScoreFromProFootballPage(t *testing.T) {
+	body := []byte("var chartData = [[1,0.5,\"Q1 15:00 GNB 0-CHI 0 50.00%\"],[2,1,\"Q4 0:00 GNB 24-CHI 17 100.00%\"]]\n")
+	visitingScore, homeScore, ok := GetFinalScoreFromProFootballPage(body, "CHI", "GNB")
+	if !ok || visitingScore != 17 || homeScore != 24 {
+		t.Errorf("We got an unexpected result: %v-%v instead of %v-%v", visitingScore, homeScore, 17, 24)
+	}
+}
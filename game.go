@@ -0,0 +1,74 @@
+package nflwp
+
+import (
+	"path"
+	"strings"
+)
+
+// A GameResult is everything we know about a single completed game.
+// Spread and PregameWP are from the home team's point of view.
+// FinalWP is the home team's win probability at the last play, so 1 means the home team won.
+// Plays is the number of chartData points the adjustments were averaged over and Points holds them.
+type GameResult struct {
+	Link                     string
+	Date                     string
+	Season                   string
+	Week                     int
+	HomeTeam                 string
+	VisitingTeam             string
+	Spread                   float64
+	HomeScore                float64
+	VisitingScore            float64
+	PregameWP                float64
+	FinalWP                  float64
+	HomeWPADJUST             float64
+	VisitingWPADJUST         float64
+	HomeSTRAIGHTWPADJUST     float64
+	VisitingSTRAIGHTWPADJUST float64
+	Plays                    int
+	Points                   []WPPoint
+}
+
+// Given a link in the format "/boxscore/YYYYMMDD0aaa.htm", return the YYYYMMDD part.
+// Links in the format "/cfb/boxscores/YYYY-MM-DD-team.html" work too.
+func GetDateFromGameLink(Link string) string {
+	Base := strings.Replace(strings.TrimSuffix(path.Base(Link), path.Ext(Link)), "-", "", -1)
+	if len(Base) < 8 {
+		return ""
+	}
+	return Base[:8]
+}
+
+// Did the home team win the game?
+func (g GameResult) HomeWon() bool {
+	return g.FinalWP == 1.0
+}
+
+// The game's contribution to the season, in the form GetDataForGameLink returns it.
+func (g GameResult) TeamData() AllTeamData {
+	var TeamData AllTeamData = NewAllTeamData()
+	TeamData[g.HomeTeam] = NewTeamData()
+	TeamData[g.HomeTeam][GAMESPLAYED] = 1.0
+	TeamData[g.HomeTeam][WPADJUST] = g.HomeWPADJUST
+	TeamData[g.HomeTeam][STRAIGHTWPADJUST] = g.HomeSTRAIGHTWPADJUST
+	TeamData[g.VisitingTeam] = NewTeamData()
+	TeamData[g.VisitingTeam][GAMESPLAYED] = 1.0
+	TeamData[g.VisitingTeam][WPADJUST] = g.VisitingWPADJUST
+	TeamData[g.VisitingTeam][STRAIGHTWPADJUST] = g.VisitingSTRAIGHTWPADJUST
+	if g.HomeWon() {
+		TeamData[g.HomeTeam][GAMESWON] += 1
+	} else {
+		TeamData[g.VisitingTeam][GAMESWON] += 1
+	}
+	return TeamData
+}
+
+// Given a list of games in the order they were played, build the season's AllTeamData with the given Aggregator.
+// If Agg is nil, CumulativeMean is used.
+func AllTeamDataFromGameResults(Games []GameResult, Agg Aggregator) AllTeamData {
+	Builder := NewSeasonBuilder(Agg)
+	for _, val := range Games {
+		Builder.AddGameResult(val)
+	}
+	return Builder.TeamData
+}
@@ -0,0 +1,32 @@
+package nflwp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetDateFromGameLink(t *testing.T) {
+	links := []string{"/boxscores/201509100nwe.htm", "/boxscores/201601030gnb.htm", "bad"}
+	expectedResults := []string{"20150910", "20160103", ""}
+	for i := 0; i < len(links); i++ {
+		result := GetDateFromGameLink(links[i])
+		if result != expectedResults[i] {
+			t.Errorf("We got an unexpected result: %v instead of %v", result, expectedResults[i])
+		}
+	}
+}
+
+func TestAllTeamDataFromGameResults(t *testing.T) {
+	games := []GameResult{
+		{HomeTeam: "NWE", VisitingTeam: "PIT", FinalWP: 1, HomeWPADJUST: 0.1, VisitingWPADJUST: -0.1, HomeSTRAIGHTWPADJUST: 0.6, VisitingSTRAIGHTWPADJUST: 0.4},
+		{HomeTeam: "BUF", VisitingTeam: "NWE", FinalWP: 0, HomeWPADJUST: -0.2, VisitingWPADJUST: 0.2, HomeSTRAIGHTWPADJUST: 0.3, VisitingSTRAIGHTWPADJUST: 0.7},
+	}
+	teamData := AllTeamDataFromGameResults(games, nil)
+	nwe := teamData["NWE"]
+	if nwe[GAMESPLAYED] != 2 || nwe[GAMESWON] != 2 || math.Abs(nwe[WPADJUST]-0.3) > 0.0005 || math.Abs(nwe[STRAIGHTWPADJUST]-1.3) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v", nwe)
+	}
+	if teamData["BUF"][GAMESWON] != 0 || teamData["PIT"][GAMESPLAYED] != 1 {
+		t.Errorf("We got an unexpected result: %v %v", teamData["BUF"], teamData["PIT"])
+	}
+}
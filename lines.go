@@ -0,0 +1,176 @@
+package nflwp
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// A LineQuote is the line a source was offering on a game at a moment.
+// Spread is from the home team's point of view. Moneylines are American odds, like -150 or +130, and are 0 if the source didn't have one.
+type LineQuote struct {
+	Source            string
+	RecordedAt        time.Time
+	Spread            float64
+	Total             float64
+	HomeMoneyline     float64
+	VisitingMoneyline float64
+}
+
+// A LineHistory holds every quote we have seen for each game, oldest first.
+// Games are keyed by their box score link, the same as GameResult.Link and the storage package.
+type LineHistory map[string][]LineQuote
+
+// Add a quote for a game, keeping the game's quotes in time order.
+func (h LineHistory) Add(Link string, Quote LineQuote) {
+	Quotes := h[Link]
+	i := sort.Search(len(Quotes), func(i int) bool { return Quotes[i].RecordedAt.After(Quote.RecordedAt) })
+	Quotes = append(Quotes, LineQuote{})
+	copy(Quotes[i+1:], Quotes[i:])
+	Quotes[i] = Quote
+	h[Link] = Quotes
+}
+
+// The quotes for a game from a source, oldest first. If Source is "", the quotes from every source are returned.
+func (h LineHistory) Quotes(Link, Source string) []LineQuote {
+	if Source == "" {
+		return h[Link]
+	}
+	var Quotes []LineQuote
+	for _, val := range h[Link] {
+		if val.Source == Source {
+			Quotes = append(Quotes, val)
+		}
+	}
+	return Quotes
+}
+
+// The first quote for a game from a source, or false if there isn't one.
+func (h LineHistory) Opening(Link, Source string) (LineQuote, bool) {
+	Quotes := h.Quotes(Link, Source)
+	if len(Quotes) == 0 {
+		return LineQuote{}, false
+	}
+	return Quotes[0], true
+}
+
+// The last quote for a game from a source before the given time, which is usually kickoff.
+// If Before is zero, the last quote is returned. Returns false if there isn't one.
+func (h LineHistory) Closing(Link, Source string, Before time.Time) (LineQuote, bool) {
+	Quotes := h.Quotes(Link, Source)
+	for i := len(Quotes) - 1; i >= 0; i-- {
+		if Before.IsZero() || Quotes[i].RecordedAt.Before(Before) {
+			return Quotes[i], true
+		}
+	}
+	return LineQuote{}, false
+}
+
+// How far the spread moved from the opening to the closing quote. A negative move is toward the home team.
+func (h LineHistory) Movement(Link, Source string) float64 {
+	Opening, ok := h.Opening(Link, Source)
+	Closing, ok2 := h.Closing(Link, Source, time.Time{})
+	if !ok || !ok2 {
+		return 0
+	}
+	return Closing.Spread - Opening.Spread
+}
+
+// Given the spread a bet was made at and which side it was on, return the points it beat the closing spread by.
+// Taking the home team at +3 when the line closes at +1 is worth 2 points; taking the visitor there costs 2.
+func ClosingLineValue(Spread, ClosingSpread float64, Home bool) float64 {
+	if Home {
+		return Spread - ClosingSpread
+	}
+	return ClosingSpread - Spread
+}
+
+// The win probability an American moneyline implies, vig included.
+func MoneylineProbability(Moneyline float64) float64 {
+	if Moneyline < 0 {
+		return -Moneyline / (100 - Moneyline)
+	}
+	return 100 / (100 + Moneyline)
+}
+
+// The American moneyline, to the nearest whole number, that implies the win probability, the other way around from MoneylineProbability.
+// Even money is +100.
+func ProbabilityMoneyline(Probability float64) float64 {
+	if Probability > 0.5 {
+		return math.Round(-100 * Probability / (1 - Probability))
+	}
+	return math.Round(100 * (1 - Probability) / Probability)
+}
+
+// The home team's win probability from the quote's moneylines with the vig taken out, or false if it doesn't have both.
+func (q LineQuote) HomeWinProbability() (float64, bool) {
+	if q.HomeMoneyline == 0 || q.VisitingMoneyline == 0 {
+		return 0, false
+	}
+	Home := MoneylineProbability(q.HomeMoneyline)
+	return Home / (Home + MoneylineProbability(q.VisitingMoneyline)), true
+}
+
+// A LineMove is how a game's spread moved from the opening to the closing quote, along with what a model predicted.
+type LineMove struct {
+	Link      string
+	Opening   float64
+	Closing   float64
+	Predicted float64
+}
+
+// Given predicted spreads keyed by game link, compare each game's opening line and prediction to how the line moved.
+// Agreement is the fraction of games that moved where the line moved toward the prediction,
+// and Correlation is the correlation between the prediction's edge on the opening line and the move.
+// This is how we check whether WPADJUST, through a Predictor, sees line moves coming.
+func (h LineHistory) CompareToPredictions(Predicted map[string]float64, Source string) ([]LineMove, float64, float64) {
+	var Moves []LineMove
+	var Moved, Agreed float64
+	var Edges, Changes []float64
+	for Link, Prediction := range Predicted {
+		Opening, ok := h.Opening(Link, Source)
+		Closing, ok2 := h.Closing(Link, Source, time.Time{})
+		if !ok || !ok2 {
+			continue
+		}
+		Moves = append(Moves, LineMove{Link: Link, Opening: Opening.Spread, Closing: Closing.Spread, Predicted: Prediction})
+		Edge, Change := Prediction-Opening.Spread, Closing.Spread-Opening.Spread
+		Edges = append(Edges, Edge)
+		Changes = append(Changes, Change)
+		if Change != 0 {
+			Moved++
+			if Edge*Change > 0 {
+				Agreed++
+			}
+		}
+	}
+	sort.Slice(Moves, func(i, j int) bool { return Moves[i].Link < Moves[j].Link })
+	Agreement := 0.0
+	if Moved > 0 {
+		Agreement = Agreed / Moved
+	}
+	return Moves, Agreement, correlation(Edges, Changes)
+}
+
+// The Pearson correlation of two lists, or 0 if either doesn't vary.
+func correlation(x, y []float64) float64 {
+	n := float64(len(x))
+	if n == 0 {
+		return 0
+	}
+	var MeanX, MeanY float64
+	for i := range x {
+		MeanX += x[i] / n
+		MeanY += y[i] / n
+	}
+	var Cov, VarX, VarY float64
+	for i := range x {
+		Cov += (x[i] - MeanX) * (y[i] - MeanY)
+		VarX += (x[i] - MeanX) * (x[i] - MeanX)
+		VarY += (y[i] - MeanY) * (y[i] - MeanY)
+	}
+	if VarX == 0 || VarY == 0 {
+		return 0
+	}
+	return Cov / math.Sqrt(VarX*VarY)
+}
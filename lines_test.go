@@ -0,0 +1,85 @@
+package nflwp
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLineHistory(t *testing.T) {
+	kickoff := time.Date(2015, 9, 10, 20, 30, 0, 0, time.UTC)
+	history := make(LineHistory)
+	link := "/boxscores/201509100nwe.htm"
+	// Added out of order on purpose.
+	history.Add(link, LineQuote{Source: "A", RecordedAt: kickoff.Add(-24 * time.Hour), Spread: -6, Total: 51})
+	history.Add(link, LineQuote{Source: "A", RecordedAt: kickoff.Add(-7 * 24 * time.Hour), Spread: -4, Total: 50})
+	history.Add(link, LineQuote{Source: "B", RecordedAt: kickoff.Add(-48 * time.Hour), Spread: -5, Total: 50.5})
+	history.Add(link, LineQuote{Source: "A", RecordedAt: kickoff.Add(time.Hour), Spread: -10, Total: 48})
+	if opening, ok := history.Opening(link, "A"); !ok || opening.Spread != -4 {
+		t.Errorf("We got an unexpected result: %v instead of %v", opening.Spread, -4)
+	}
+	if closing, ok := history.Closing(link, "A", kickoff); !ok || closing.Spread != -6 {
+		t.Errorf("We got an unexpected result: %v instead of %v", closing.Spread, -6)
+	}
+	if closing, ok := history.Closing(link, "", time.Time{}); !ok || closing.Spread != -10 {
+		t.Errorf("We got an unexpected result: %v instead of %v", closing.Spread, -10)
+	}
+	if quotes := history.Quotes(link, "B"); len(quotes) != 1 || len(history.Quotes(link, "")) != 4 {
+		t.Errorf("We got an unexpected result: %v", quotes)
+	}
+	if move := history.Movement(link, "B"); move != 0 {
+		t.Errorf("We got an unexpected result: %v instead of %v", move, 0)
+	}
+	if _, ok := history.Opening("/boxscores/nothing.htm", ""); ok {
+		t.Errorf("We didn't expect an opening line")
+	}
+}
+
+func TestClosingLineValue(t *testing.T) {
+	spreads := []float64{3, 3, -7, -7}
+	closing := []float64{1, 1, -9, -9}
+	home := []bool{true, false, true, false}
+	expectedResults := []float64{2, -2, 2, -2}
+	for i := 0; i < len(spreads); i++ {
+		result := ClosingLineValue(spreads[i], closing[i], home[i])
+		if result != expectedResults[i] {
+			t.Errorf("We got an unexpected result: %v instead of %v", result, expectedResults[i])
+		}
+	}
+	if result := MoneylineProbability(-150); math.Abs(result-0.6) > 1e-9 {
+		t.Errorf("We got an unexpected result: %v instead of %v", result, 0.6)
+	}
+	for _, moneyline := range []float64{-150, -101, 100, 110} {
+		if result := ProbabilityMoneyline(MoneylineProbability(moneyline)); result != moneyline {
+			t.Errorf("We got an unexpected result: %v instead of %v", result, moneyline)
+		}
+	}
+	if result, ok := (LineQuote{HomeMoneyline: -110, VisitingMoneyline: -110}).HomeWinProbability(); !ok || math.Abs(result-0.5) > 1e-9 {
+		t.Errorf("We got an unexpected result: %v instead of %v", result, 0.5)
+	}
+}
+
+func TestCompareToPredictions(t *testing.T) {
+	start := time.Date(2015, 9, 1, 0, 0, 0, 0, time.UTC)
+	history := make(LineHistory)
+	opening := []float64{-3, 2, -7, 1}
+	closing := []float64{-5, 3, -7, 2}
+	predicted := make(map[string]float64)
+	predictions := []float64{-6, 0, -8, 4}
+	for i, link := range []string{"a", "b", "c", "d"} {
+		history.Add(link, LineQuote{RecordedAt: start, Spread: opening[i]})
+		history.Add(link, LineQuote{RecordedAt: start.Add(time.Hour), Spread: closing[i]})
+		predicted[link] = predictions[i]
+	}
+	moves, agreement, correlation := history.CompareToPredictions(predicted, "")
+	if len(moves) != 4 || moves[0].Link != "a" {
+		t.Errorf("We got an unexpected result: %v", moves)
+	}
+	// a and d moved toward the prediction, b moved away and c didn't move.
+	if math.Abs(agreement-2.0/3) > 1e-9 {
+		t.Errorf("We got an unexpected result: %v instead of %v", agreement, 2.0/3)
+	}
+	if correlation <= 0 || correlation > 1 {
+		t.Errorf("We got an unexpected result: %v", correlation)
+	}
+}
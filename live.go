@@ -0,0 +1,192 @@
+package nflwp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// A LivePlay is a point of a game's win probability chart along with the score after the play.
+type LivePlay struct {
+	WPPoint
+	HomeScore     float64
+	VisitingScore float64
+}
+
+// A LiveGame is what a PlaySource knows about a game in progress.
+// Spread is from the home team's point of view and Plays are every play so far, oldest first.
+type LiveGame struct {
+	HomeTeam     string
+	VisitingTeam string
+	Spread       float64
+	Plays        []LivePlay
+	Final        bool
+}
+
+// A PlaySource gives the play-by-play of a game in progress. Each call returns the game as it stands,
+// with every play so far, and Final set once the game is over.
+type PlaySource interface {
+	Game() (LiveGame, error)
+}
+
+// A LiveUpdate is sent by a LiveTracker for each new play. Everything is from the home team's point of view.
+// MarketWP is what the pregame spread gives with the current score and clock, see LiveWinProbability.
+// ModelWP is the play-by-play source's own win probability, and the adjustments are the game's
+// WPADJUST and STRAIGHTWPADJUST through this play. Final is set on the last update of a game.
+type LiveUpdate struct {
+	HomeTeam             string
+	VisitingTeam         string
+	Index                int
+	Play                 LivePlay
+	MarketWP             float64
+	ModelWP              float64
+	HomeWPADJUST         float64
+	HomeSTRAIGHTWPADJUST float64
+	Final                bool
+}
+
+// How often a LiveTracker polls its PlaySource when its Interval isn't positive.
+const defaultLiveInterval = 30 * time.Second
+
+// A LiveTracker follows a game by polling a PlaySource every Interval, or every 30 seconds if it isn't positive.
+// The running adjustments use Method and Sport the same way GetGameResult does.
+type LiveTracker struct {
+	Source   PlaySource
+	Interval time.Duration
+	Method   AdjustmentMethod
+	Sport    Sport
+}
+
+// Make a LiveTracker for the NFL game from the source, polling every 30 seconds.
+func NewLiveTracker(Source PlaySource) *LiveTracker {
+	return &LiveTracker{Source: Source, Interval: defaultLiveInterval, Method: PERPLAY, Sport: NFL}
+}
+
+// Start following the game. An update is sent for every play as it shows up, and the channel is closed
+// after the final update or when the context is done. A game that is over before it has any plays gets a single
+// final update with an Index of -1 and no play. Errors from the source are printed and the source
+// is polled again at the next interval.
+func (t *LiveTracker) Track(ctx context.Context) <-chan LiveUpdate {
+	Updates := make(chan LiveUpdate)
+	Interval := t.Interval
+	if Interval <= 0 {
+		Interval = defaultLiveInterval
+	}
+	go func() {
+		defer close(Updates)
+		Ticker := time.NewTicker(Interval)
+		defer Ticker.Stop()
+		var Last LiveUpdate
+		Seen := 0
+		for {
+			Game, err := t.Source.Game()
+			if err != nil {
+				fmt.Println("Error: ", err)
+			} else {
+				for i := Seen; i < len(Game.Plays); i++ {
+					Last = t.update(Game, i)
+					if !send(ctx, Updates, Last) {
+						return
+					}
+				}
+				Seen = len(Game.Plays)
+				if Game.Final {
+					// The game can end without a new play, so let the last update say so.
+					if Seen == 0 {
+						Last = LiveUpdate{HomeTeam: Game.HomeTeam, VisitingTeam: Game.VisitingTeam, Index: -1}
+					}
+					if !Last.Final {
+						Last.Final = true
+						send(ctx, Updates, Last)
+					}
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-Ticker.C:
+			}
+		}
+	}()
+	return Updates
+}
+
+// Send the update unless the context is done first.
+func send(ctx context.Context, Updates chan<- LiveUpdate, Update LiveUpdate) bool {
+	select {
+	case Updates <- Update:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// The update for play i of the game.
+func (t *LiveTracker) update(Game LiveGame, i int) LiveUpdate {
+	Play := Game.Plays[i]
+	Points := make([]WPPoint, i+1)
+	for j := range Points {
+		Points[j] = Game.Plays[j].WPPoint
+	}
+	Result := GameResult{Spread: Game.Spread}
+	Result.SetAdjustmentsForSport(Points, t.Method, t.Sport)
+	return LiveUpdate{
+		HomeTeam:             Game.HomeTeam,
+		VisitingTeam:         Game.VisitingTeam,
+		Index:                i,
+		Play:                 Play,
+		MarketWP:             t.Sport.LiveWinProbability(Play.HomeScore-Play.VisitingScore, Game.Spread, Play.Remaining),
+		ModelWP:              Play.HomeWP,
+		HomeWPADJUST:         Result.HomeWPADJUST,
+		HomeSTRAIGHTWPADJUST: Result.HomeSTRAIGHTWPADJUST,
+		Final:                Game.Final && i == len(Game.Plays)-1,
+	}
+}
+
+// Given the info for a play, like "\"Q2 15:00 GNB 7-CHI 0 70.00%\"", return the home and visiting scores after it.
+// Returns false if the score isn't there.
+func PlayScore(PlayInfo, HomeTeam, VisitingTeam string) (float64, float64, bool) {
+	Scores := finalScoreRegexp.FindStringSubmatch(PlayInfo)
+	if Scores == nil {
+		return 0, 0, false
+	}
+	Score := make(map[string]float64)
+	for i := 1; i < 5; i += 2 {
+		Score[Scores[i]], _ = strconv.ParseFloat(Scores[i+1], 64)
+	}
+	HomeScore, ok := Score[HomeTeam]
+	VisitingScore, ok2 := Score[VisitingTeam]
+	return HomeScore, VisitingScore, ok && ok2
+}
+
+// A ReplaySource plays back a finished game as though it were live, giving PlaysPerPoll more plays each time it is polled.
+// It is how a LiveTracker is tested, and how to watch one against an old game.
+// Scores are read from each play's info and carried over from the play before when they aren't there.
+type ReplaySource struct {
+	Result       GameResult
+	PlaysPerPoll int
+	shown        int
+}
+
+func (r *ReplaySource) Game() (LiveGame, error) {
+	if r.PlaysPerPoll < 1 {
+		return LiveGame{}, fmt.Errorf("a replay has to show at least one play a poll, not %v", r.PlaysPerPoll)
+	}
+	r.shown += r.PlaysPerPoll
+	if r.shown > len(r.Result.Points) {
+		r.shown = len(r.Result.Points)
+	}
+	Game := LiveGame{HomeTeam: r.Result.HomeTeam, VisitingTeam: r.Result.VisitingTeam, Spread: r.Result.Spread,
+		Plays: make([]LivePlay, r.shown), Final: r.shown == len(r.Result.Points)}
+	var Previous LivePlay
+	for i := range Game.Plays {
+		Game.Plays[i] = LivePlay{WPPoint: r.Result.Points[i], HomeScore: Previous.HomeScore, VisitingScore: Previous.VisitingScore}
+		if Home, Visitor, ok := PlayScore(Game.Plays[i].PlayInfo, r.Result.HomeTeam, r.Result.VisitingTeam); ok {
+			Game.Plays[i].HomeScore, Game.Plays[i].VisitingScore = Home, Visitor
+		}
+		Previous = Game.Plays[i]
+	}
+	return Game, nil
+}
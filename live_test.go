@@ -0,0 +1,125 @@
+package nflwp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func replayGame() GameResult {
+	return GameResult{HomeTeam: "GNB", VisitingTeam: "CHI", Spread: -3, Points: ParseChartData([]byte(testChartData))}
+}
+
+func collect(Updates <-chan LiveUpdate) []LiveUpdate {
+	var Result []LiveUpdate
+	for val := range Updates {
+		Result = append(Result, val)
+	}
+	return Result
+}
+
+func TestLiveTracker(t *testing.T) {
+	tracker := NewLiveTracker(&ReplaySource{Result: replayGame(), PlaysPerPoll: 3})
+	tracker.Interval = time.Millisecond
+	updates := collect(tracker.Track(context.Background()))
+	if len(updates) != 4 {
+		t.Fatalf("We got an unexpected result: %v updates instead of 4", len(updates))
+	}
+	homeScores := []float64{0, 7, 7, 14}
+	for i, val := range updates {
+		if val.Index != i || val.Play.HomeScore != homeScores[i] || val.ModelWP != val.Play.HomeWP || val.Final != (i == 3) || val.HomeTeam != "GNB" {
+			t.Errorf("We got an unexpected result: %+v", val)
+		}
+	}
+	if expected := WinProbability(0, -3, STDDEV); math.Abs(updates[0].MarketWP-expected) > 1e-9 {
+		t.Errorf("We got an unexpected result: %v instead of %v", updates[0].MarketWP, expected)
+	}
+	if expected := LiveWinProbability(7, -3, 45); math.Abs(updates[1].MarketWP-expected) > 1e-9 {
+		t.Errorf("We got an unexpected result: %v instead of %v", updates[1].MarketWP, expected)
+	}
+	if updates[3].MarketWP != 1 {
+		t.Errorf("We got an unexpected result: %v instead of %v", updates[3].MarketWP, 1)
+	}
+	// The running adjustment ends where the finished game's does.
+	game := replayGame()
+	game.SetAdjustments(game.Points, PERPLAY)
+	if updates[3].HomeWPADJUST != game.HomeWPADJUST || updates[3].HomeSTRAIGHTWPADJUST != game.HomeSTRAIGHTWPADJUST {
+		t.Errorf("We got an unexpected result: %v instead of %v", updates[3].HomeWPADJUST, game.HomeWPADJUST)
+	}
+	partial := replayGame()
+	partial.SetAdjustments(partial.Points[:2], PERPLAY)
+	if updates[1].HomeWPADJUST != partial.HomeWPADJUST {
+		t.Errorf("We got an unexpected result: %v instead of %v", updates[1].HomeWPADJUST, partial.HomeWPADJUST)
+	}
+}
+
+// A source that fails the first time and says the game is over a poll after the last play.
+type flakySource struct {
+	polls int
+}
+
+func (f *flakySource) Game() (LiveGame, error) {
+	f.polls++
+	if f.polls == 1 {
+		return LiveGame{}, errors.New("the page isn't up yet")
+	}
+	game := LiveGame{HomeTeam: "GNB", VisitingTeam: "CHI", Plays: []LivePlay{{WPPoint: WPPoint{HomeWP: 0.5, Remaining: 60, TotalMinutes: 60}}}}
+	game.Final = f.polls > 2
+	return game, nil
+}
+
+func TestLiveTrackerFinalWithoutNewPlay(t *testing.T) {
+	tracker := NewLiveTracker(&flakySource{})
+	tracker.Interval = time.Millisecond
+	updates := collect(tracker.Track(context.Background()))
+	if len(updates) != 2 || updates[0].Final || !updates[1].Final || updates[1].Index != 0 {
+		t.Errorf("We got an unexpected result: %+v", updates)
+	}
+}
+
+// A source for a game that is over without any plays, like one that was called off.
+type finishedSource struct{}
+
+func (finishedSource) Game() (LiveGame, error) {
+	return LiveGame{HomeTeam: "GNB", VisitingTeam: "CHI", Final: true}, nil
+}
+
+func TestLiveTrackerFinalWithoutPlays(t *testing.T) {
+	// An Interval of 0 would make the ticker panic, so the tracker polls at its default instead.
+	tracker := &LiveTracker{Source: finishedSource{}, Sport: NFL}
+	updates := collect(tracker.Track(context.Background()))
+	if len(updates) != 1 || !updates[0].Final || updates[0].Index != -1 || updates[0].HomeTeam != "GNB" {
+		t.Errorf("We got an unexpected result: %+v", updates)
+	}
+}
+
+func TestLiveTrackerCancel(t *testing.T) {
+	tracker := NewLiveTracker(&ReplaySource{Result: replayGame(), PlaysPerPoll: 1})
+	tracker.Interval = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := tracker.Track(ctx)
+	if update := <-updates; update.Index != 0 {
+		t.Errorf("We got an unexpected result: %+v", update)
+	}
+	cancel()
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Errorf("We didn't expect another update after cancelling")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("The tracker didn't stop after cancelling")
+	}
+}
+
+func TestPlayScore(t *testing.T) {
+	home, visitor, ok := PlayScore("\"Q2 15:00 GNB 7-CHI 3 70.00%\"", "CHI", "GNB")
+	if !ok || home != 3 || visitor != 7 {
+		t.Errorf("We got an unexpected result: %v %v %v", home, visitor, ok)
+	}
+	if _, _, ok = PlayScore("\"Q2 15:00\"", "CHI", "GNB"); ok {
+		t.Errorf("We didn't expect a score")
+	}
+}
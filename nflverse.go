@@ -0,0 +1,254 @@
+package nflwp
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The nflverse team codes that aren't the same as pro-football-reference's.
+// Teams that moved keep the PFR code of the franchise, like PFR does.
+var nflverseTeams = map[string]string{
+	"ARI": "CRD", "BAL": "RAV", "GB": "GNB", "HOU": "HTX", "IND": "CLT", "KC": "KAN",
+	"LA": "RAM", "LAR": "RAM", "STL": "RAM", "LAC": "SDG", "SD": "SDG", "LV": "RAI", "OAK": "RAI",
+	"NE": "NWE", "NO": "NOR", "SF": "SFO", "TB": "TAM", "TEN": "OTI",
+}
+
+// Given an nflverse team code, like "NE" or "LV", return the pro-football-reference abbreviation, or "" if we don't know it.
+func GetTeamAbbrFromNflverse(Code string) string {
+	Code = strings.ToUpper(strings.TrimSpace(Code))
+	if Abbr, ok := nflverseTeams[Code]; ok {
+		return Abbr
+	}
+	if NFL.Teams.Float(Code) != 0 {
+		return Code
+	}
+	return ""
+}
+
+// The columns of an nflverse play-by-play file that the importer reads.
+var nflverseColumns = []string{"game_id", "season", "week", "game_date", "home_team", "away_team", "spread_line",
+	"home_score", "away_score", "qtr", "time", "total_home_score", "total_away_score", "desc"}
+
+// An NflverseImporter reads nflfastR/nflverse play-by-play CSV files into GameResults.
+// WPColumn is the column with the home team's win probability, "home_wp" for nflfastR's model or
+// "vegas_home_wp" for the one that knows the spread, and the adjustments are set with Method.
+type NflverseImporter struct {
+	WPColumn string
+	Method   AdjustmentMethod
+}
+
+// Make an NflverseImporter that reads "home_wp" and weights every play the same, like PFR games are by default.
+func NewNflverseImporter() NflverseImporter {
+	return NflverseImporter{WPColumn: "home_wp", Method: PERPLAY}
+}
+
+// Read a play-by-play file, like "play_by_play_2015.csv" or "play_by_play_2015.csv.gz", into GameResults.
+func (n NflverseImporter) ReadFile(Path string) ([]GameResult, error) {
+	file, err := os.Open(Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if strings.HasSuffix(Path, ".gz") {
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+	return n.Read(r)
+}
+
+// Read play-by-play rows into one GameResult per game, in the order the games first show up.
+// Each play with a win probability and a clock becomes a WPPoint whose PlayInfo looks like PFR's, for example
+// "\"Q1 15:00 PIT 0-NWE 0 60.00%\"", so the adjustments are found the same way as for a PFR chart.
+// Overtime is as long as it was that week, see Sport.ForWeek.
+// The "END GAME" row is the final point, at 1, 0 or 0.5 for a home win, loss or tie.
+// Link is the game's pro-football-reference link so the two can be compared, and Spread is the negative of spread_line,
+// since nflverse counts points the home team is favored by.
+func (n NflverseImporter) Read(r io.Reader) ([]GameResult, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	Header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	Columns := make(map[string]int)
+	for i, val := range Header {
+		Columns[val] = i
+	}
+	for _, val := range append(nflverseColumns, n.WPColumn) {
+		if _, ok := Columns[val]; !ok {
+			return nil, fmt.Errorf("the play-by-play file has no %v column", val)
+		}
+	}
+	var Games []GameResult
+	var Sports []Sport
+	Index := make(map[string]int)
+	for {
+		Row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		Line, _ := cr.FieldPos(0)
+		// Optional columns like season_type are "" if the file doesn't have them.
+		Get := func(Name string) string {
+			if i, ok := Columns[Name]; ok {
+				return Row[i]
+			}
+			return ""
+		}
+		i, ok := Index[Get("game_id")]
+		if !ok {
+			Game, err := n.newGame(Get)
+			if err != nil {
+				return nil, fmt.Errorf("line %v: %v", Line, err)
+			}
+			i = len(Games)
+			Index[Get("game_id")] = i
+			Games = append(Games, Game)
+			Sports = append(Sports, NFL.ForWeek(Game.Season, Game.Week, Get("season_type")))
+		}
+		Point, ok, err := n.point(Get, Games[i], Sports[i])
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", Line, err)
+		}
+		if ok {
+			Games[i].Points = append(Games[i].Points, Point)
+		}
+	}
+	for i := range Games {
+		if len(Games[i].Points) == 0 {
+			continue
+		}
+		Games[i].PregameWP = Games[i].Points[0].HomeWP
+		Games[i].FinalWP = Games[i].Points[len(Games[i].Points)-1].HomeWP
+		Games[i].SetAdjustmentsForSport(Games[i].Points, n.Method, Sports[i])
+	}
+	return Games, nil
+}
+
+// Start a game from its first row.
+func (n NflverseImporter) newGame(Get func(string) string) (GameResult, error) {
+	var Game GameResult
+	var err error
+	if Game.HomeTeam = GetTeamAbbrFromNflverse(Get("home_team")); Game.HomeTeam == "" {
+		return Game, fmt.Errorf("we don't know the team %q", Get("home_team"))
+	}
+	if Game.VisitingTeam = GetTeamAbbrFromNflverse(Get("away_team")); Game.VisitingTeam == "" {
+		return Game, fmt.Errorf("we don't know the team %q", Get("away_team"))
+	}
+	Game.Season = Get("season")
+	if Game.Week, err = strconv.Atoi(Get("week")); err != nil {
+		return Game, fmt.Errorf("bad week %q", Get("week"))
+	}
+	Game.Date = strings.Replace(Get("game_date"), "-", "", -1)
+	if len(Game.Date) != 8 {
+		return Game, fmt.Errorf("bad date %q", Get("game_date"))
+	}
+	Game.Link = NFL.Source.GamePath(Game.Date, Game.HomeTeam)
+	if Game.HomeScore, err = strconv.ParseFloat(Get("home_score"), 64); err != nil {
+		return Game, fmt.Errorf("bad home score %q", Get("home_score"))
+	}
+	if Game.VisitingScore, err = strconv.ParseFloat(Get("away_score"), 64); err != nil {
+		return Game, fmt.Errorf("bad away score %q", Get("away_score"))
+	}
+	// Older seasons don't always have a line.
+	if Line, err := strconv.ParseFloat(Get("spread_line"), 64); err == nil {
+		Game.Spread = -Line
+	}
+	return Game, nil
+}
+
+// The WPPoint for a row, or false if the row has no win probability or clock.
+func (n NflverseImporter) point(Get func(string) string, Game GameResult, ThisSport Sport) (WPPoint, bool, error) {
+	HomeWP, err := strconv.ParseFloat(Get(n.WPColumn), 64)
+	HomeScore, VisitingScore := Get("total_home_score"), Get("total_away_score")
+	Clock := Get("time")
+	EndGame := strings.EqualFold(Get("desc"), "END GAME")
+	if Clock == "" && !EndGame {
+		return WPPoint{}, false, nil
+	}
+	if EndGame {
+		HomeWP, err = 0.5, nil
+		if Game.HomeScore > Game.VisitingScore {
+			HomeWP = 1
+		} else if Game.HomeScore < Game.VisitingScore {
+			HomeWP = 0
+		}
+		HomeScore, VisitingScore = strconv.FormatFloat(Game.HomeScore, 'f', -1, 64), strconv.FormatFloat(Game.VisitingScore, 'f', -1, 64)
+		Clock = "0:00"
+	}
+	if err != nil || math.IsNaN(HomeWP) {
+		return WPPoint{}, false, nil
+	}
+	Quarter, err := strconv.Atoi(Get("qtr"))
+	if err != nil {
+		return WPPoint{}, false, fmt.Errorf("bad quarter %q", Get("qtr"))
+	}
+	Period := "Q" + strconv.Itoa(Quarter)
+	if Quarter > 4 {
+		Period = "OT"
+	}
+	PlayInfo := fmt.Sprintf("\"%v %v %v %v-%v %v %.2f%%\"", Period, Clock, Game.VisitingTeam, VisitingScore, Game.HomeTeam, HomeScore, 100*HomeWP)
+	Point := WPPoint{HomeWP: HomeWP, PlayInfo: PlayInfo}
+	var ok bool
+	if Point.Quarter, Point.Remaining, Point.TotalMinutes, ok = ThisSport.ParsePlayClock(PlayInfo); !ok {
+		return WPPoint{}, false, fmt.Errorf("bad clock %q", Clock)
+	}
+	Point.Elapsed = Point.TotalMinutes - Point.Remaining
+	return Point, true, nil
+}
+
+// An AdjustmentDifference compares a game's adjustments from two sources, like PFR and nflverse.
+// The differences are the second source's minus the first's.
+type AdjustmentDifference struct {
+	Link              string
+	HomeTeam          string
+	VisitingTeam      string
+	WPADJUST          float64
+	STRAIGHTWPADJUST  float64
+	PregameWP         float64
+	MissingFromSecond bool
+}
+
+// Given the same games from two sources, compare the home team's adjustments game by game, matching games by Link.
+// Games only in the first list are marked MissingFromSecond, and games only in the second are left out.
+// Returns the differences and the mean absolute difference in WPADJUST over the games in both.
+func CompareAdjustments(First, Second []GameResult) ([]AdjustmentDifference, float64) {
+	ByLink := make(map[string]GameResult, len(Second))
+	for _, val := range Second {
+		ByLink[val.Link] = val
+	}
+	var Differences []AdjustmentDifference
+	Total, Count := 0.0, 0.0
+	for _, val := range First {
+		Difference := AdjustmentDifference{Link: val.Link, HomeTeam: val.HomeTeam, VisitingTeam: val.VisitingTeam}
+		Other, ok := ByLink[val.Link]
+		if !ok {
+			Difference.MissingFromSecond = true
+		} else {
+			Difference.WPADJUST = Other.HomeWPADJUST - val.HomeWPADJUST
+			Difference.STRAIGHTWPADJUST = Other.HomeSTRAIGHTWPADJUST - val.HomeSTRAIGHTWPADJUST
+			Difference.PregameWP = Other.PregameWP - val.PregameWP
+			Total += math.Abs(Difference.WPADJUST)
+			Count++
+		}
+		Differences = append(Differences, Difference)
+	}
+	if Count == 0 {
+		return Differences, 0
+	}
+	return Differences, Total / Count
+}
@@ -0,0 +1,81 @@
+package nflwp
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGetTeamAbbrFromNflverse(t *testing.T) {
+	codes := []string{"NE", "gb", "LV", "OAK", "LAC", "LA", "PIT", "XYZ"}
+	expectedResults := []string{"NWE", "GNB", "RAI", "RAI", "SDG", "RAM", "PIT", ""}
+	for i := 0; i < len(codes); i++ {
+		if result := GetTeamAbbrFromNflverse(codes[i]); result != expectedResults[i] {
+			t.Errorf("We got an unexpected result: %v instead of %v", result, expectedResults[i])
+		}
+	}
+}
+
+func TestNflverseImporter(t *testing.T) {
+	games, err := NewNflverseImporter().ReadFile("testdata/nflverse_pbp.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 2 {
+		t.Fatalf("We got an unexpected result: %v games instead of 2", len(games))
+	}
+	game := games[0]
+	if game.Link != "/boxscores/201509100nwe.htm" || game.HomeTeam != "NWE" || game.VisitingTeam != "PIT" || game.Season != "2015" || game.Week != 1 ||
+		game.Spread != -7 || game.HomeScore != 28 || game.VisitingScore != 21 || game.PregameWP != 0.6 || !game.HomeWon() {
+		t.Errorf("We got an unexpected result: %+v", game)
+	}
+	// The rows without a win probability are skipped and the END GAME row is the final point.
+	expectedInfo := []string{"\"Q1 15:00 PIT 0-NWE 0 60.00%\"", "\"Q2 15:00 PIT 0-NWE 7 75.00%\"", "\"Q4 15:00 PIT 14-NWE 21 80.00%\"", "\"Q4 0:00 PIT 21-NWE 28 100.00%\""}
+	expectedRemaining := []float64{60, 45, 15, 0}
+	if len(game.Points) != len(expectedInfo) {
+		t.Fatalf("We got an unexpected result: %+v", game.Points)
+	}
+	for i, val := range game.Points {
+		if val.PlayInfo != expectedInfo[i] || val.Remaining != expectedRemaining[i] || val.Elapsed != 60-expectedRemaining[i] {
+			t.Errorf("We got an unexpected result: %+v", val)
+		}
+	}
+	// The adjustments are the same as for a PFR chart with the same points.
+	expected := GameResult{Spread: -7}
+	expected.SetAdjustments(game.Points, PERPLAY)
+	if game.HomeWPADJUST != expected.HomeWPADJUST || game.Plays != 4 {
+		t.Errorf("We got an unexpected result: %v instead of %v", game.HomeWPADJUST, expected.HomeWPADJUST)
+	}
+	if home, visitor, ok := PlayScore(game.Points[2].PlayInfo, "NWE", "PIT"); !ok || home != 21 || visitor != 14 {
+		t.Errorf("We got an unexpected result: %v %v %v", home, visitor, ok)
+	}
+	if games[1].HomeTeam != "CHI" || games[1].VisitingTeam != "GNB" || games[1].Spread != 6 || games[1].FinalWP != 0 || games[1].Points[1].Remaining != 22.5 {
+		t.Errorf("We got an unexpected result: %+v", games[1])
+	}
+
+	vegas := NflverseImporter{WPColumn: "vegas_home_wp", Method: PERPLAY}
+	vegasGames, err := vegas.ReadFile("testdata/nflverse_pbp.csv")
+	if err != nil || vegasGames[0].PregameWP != 0.72 {
+		t.Errorf("We got an unexpected result: %v %v", vegasGames[0].PregameWP, err)
+	}
+	differences, mean := CompareAdjustments(games, vegasGames[:1])
+	if len(differences) != 2 || !differences[1].MissingFromSecond || math.Abs(differences[0].PregameWP-0.12) > 1e-9 ||
+		math.Abs(mean-math.Abs(vegasGames[0].HomeWPADJUST-games[0].HomeWPADJUST)) > 1e-9 {
+		t.Errorf("We got an unexpected result: %+v %v", differences, mean)
+	}
+}
+
+func TestNflverseImporterErrors(t *testing.T) {
+	body, err := os.ReadFile("testdata/nflverse_pbp.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = (NflverseImporter{WPColumn: "wp", Method: PERPLAY}).Read(strings.NewReader(string(body))); err == nil {
+		t.Errorf("We expected an error for a missing column")
+	}
+	unknown := strings.Replace(string(body), ",CHI,GB,", ",CHI,XYZ,", 1)
+	if _, err = NewNflverseImporter().Read(strings.NewReader(unknown)); err == nil || !strings.Contains(err.Error(), "line 8") {
+		t.Errorf("We got an unexpected result: %v", err)
+	}
+}
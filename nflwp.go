@@ -1,7 +1,6 @@
 package nflwp
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"io/ioutil"
@@ -109,6 +108,12 @@ func WinProbability(scoreDiff, spread, stdev float64) float64 {
 	return 1 - cdf(scoreDiff+0.5, -spread, stdev) + 0.5*(cdf(scoreDiff+0.5, -spread, stdev)-cdf(scoreDiff-0.5, -spread, stdev))
 }
 
+// Given the home team's lead, the spread and the minutes left in an NFL game, return the home team's win probability.
+// See Sport.LiveWinProbability.
+func LiveWinProbability(Margin, Spread, Remaining float64) float64 {
+	return NFL.LiveWinProbability(Margin, Spread, Remaining)
+}
+
 // Given a haystack and two needles, return a slice containing all text occuring between
 // needle1 and needle2
 // Returns nil on error or if nothing is found.
@@ -140,11 +145,11 @@ func CheckFileExists(filename, url string) []byte {
 	if err != nil {
 		if os.IsNotExist(err) {
 			response, err := http.Get(url)
-			defer response.Body.Close()
 			if err != nil {
 				fmt.Println("Error: ", err)
 				return nil
 			}
+			defer response.Body.Close()
 			body, err = ioutil.ReadAll(response.Body)
 			if err != nil {
 				fmt.Println("Error: ", err)
@@ -179,52 +184,33 @@ func CheckFileExists(filename, url string) []byte {
 // Given the spread of a game and the info for a given play,
 // calculate the probability the spread predicts at this point of the game
 func FindAdjustedStartingProbability(Spread float64, PlayInfo string, PreviousAdjustment float64) float64 {
-	var err error
-	var Index int
-	var Quarter, MinsRemaining, Tmp float64
-	TotalMins := 60.0
-	if strings.Compare(string(PlayInfo[1]), "O") == 0 {
-		Quarter = 4
-		TotalMins += 15
-	} else {
-		Quarter, err = strconv.ParseFloat(string(PlayInfo[2]), 64)
-		if err != nil {
-			if strings.Compare(PlayInfo, "null") != 0 {
-				fmt.Println("ERROR1: ", err, PlayInfo)
-			}
-			return PreviousAdjustment
-		}
-	}
-	PlayInfo = PlayInfo[4:]
-	Index = strings.Index(PlayInfo, ":")
-	Tmp, err = strconv.ParseFloat(PlayInfo[:Index], 64)
-	if err != nil {
-		fmt.Println("ERROR2: ", err, PlayInfo)
-		return WinProbability(0, Spread, STDDEV/math.Sqrt(TotalMins/((5.0-Quarter)*15.0)))
-	}
-	MinsRemaining = Tmp
-	Tmp, err = strconv.ParseFloat(PlayInfo[Index+1:Index+3], 64)
-	if err != nil {
-		fmt.Println("ERROR3: ", err, PlayInfo)
-		return WinProbability(0, Spread*((4.0-Quarter)*15.0+MinsRemaining)/TotalMins, STDDEV/math.Sqrt(TotalMins/((4.0-Quarter)*15.0+MinsRemaining)))
-	}
-	MinsRemaining += Tmp / 60
-	AdjustmentFactor := TotalMins / ((4.0-Quarter)*15.0 + MinsRemaining)
-	return WinProbability(Spread*(1-(1/AdjustmentFactor)), Spread/AdjustmentFactor, STDDEV/math.Sqrt(AdjustmentFactor))
+	return NFL.AdjustedStartingProbability(Spread, PlayInfo, PreviousAdjustment)
 }
 
 // Given the HTML text of a gamelink, we get the team abbreviations
+// from the axis labels of pro-football-reference's win probability chart.
+// Empty names are returned if the page doesn't have the chart.
 func GetTeamNames(HTML string) (string, string) {
 	WhereToStartLooking := strings.Index(HTML, "vAxis")
 	WhereToStopLooking := strings.Index(HTML, "hAxis")
+	if WhereToStartLooking == -1 || WhereToStopLooking < WhereToStartLooking {
+		return "", ""
+	}
 	HTML = HTML[WhereToStartLooking:WhereToStopLooking]
 	SplitAtQuote := strings.Split(HTML, "\"")
+	if len(SplitAtQuote) < 4 {
+		return "", ""
+	}
 	return SplitAtQuote[1], SplitAtQuote[len(SplitAtQuote)-2]
 }
 
+// The spread GetSpreadFromProFootballPage returns when the page doesn't have a line.
+const NOSPREAD = 1234.0
+
+// Given a box score page, return the home team's spread, or NOSPREAD if the page doesn't have one.
 func GetSpreadFromProFootballPage(body []byte, VisitingTeam, HomeTeam string) float64 {
 	IndexOfLine := bytes.Index(body, []byte("Vegas Line"))
-	Spread := 1234.0
+	Spread := NOSPREAD
 	var err error
 	if IndexOfLine != -1 {
 		body = body[IndexOfLine : IndexOfLine+bytes.Index(body[IndexOfLine:], []byte("</td>"))]
@@ -237,108 +223,101 @@ func GetSpreadFromProFootballPage(body []byte, VisitingTeam, HomeTeam string) fl
 				return 0
 			}
 			fmt.Printf("ERROR: Error getting the spreads for game %v at %v: %v.\n", VisitingTeam, HomeTeam, SpreadAsString)
-			return 1234
+			return NOSPREAD
 		}
 	}
 	return Spread
 }
 
+// Given the HTML text of a gamelink, we find the final score from the last play in the chartData.
+// The play info looks like "Q4 0:00 GNB 24-CHI 17 100.00%".
+// Returns false if the score cannot be found.
+func GetFinalScoreFromProFootballPage(body []byte, VisitingTeam, HomeTeam string) (float64, float64, bool) {
+	Data := FindAllBetween(body, "var chartData = ", "\n")
+	if Data == nil {
+		return 0, 0, false
+	}
+	Scores := finalScoreRegexp.FindAllStringSubmatch(Data[0], -1)
+	if Scores == nil {
+		fmt.Printf("ERROR: Cannot find the final score for game %v at %v.\n", VisitingTeam, HomeTeam)
+		return 0, 0, false
+	}
+	Score := make(map[string]float64)
+	for i := 1; i < 5; i += 2 {
+		Score[Scores[len(Scores)-1][i]], _ = strconv.ParseFloat(Scores[len(Scores)-1][i+1], 64)
+	}
+	VisitingScore, ok := Score[VisitingTeam]
+	HomeScore, ok2 := Score[HomeTeam]
+	return VisitingScore, HomeScore, ok && ok2
+}
+
+var finalScoreRegexp = regexp.MustCompile(`([A-Z]{2,3}) (\d+)-([A-Z]{2,3}) (\d+)`)
+
 // pro-football-reference.com puts the spreads for the game on the page after the game starts.
 // Here, we peek at the next week to get the spreads.
 func PeekAheadForSpreads(TeamData AllTeamData, Year, Week string) AllTeamData {
+	return TeamData.AddUpcomingGames(PeekAheadForGames(Year, Week))
+}
+
+// Like PeekAheadForSpreads, but return the games for the week instead of filling in a TeamData.
+// Games whose page doesn't have a line yet are left out.
+func PeekAheadForGames(Year, Week string) []UpcomingGame {
 	var HomeTeam, VisitingTeam string
-	url := "http://www.pro-football-reference.com/years/" + Year + "/week_" + Week + ".htm"
-	body := CheckFileExists("NFL-"+Year+"-Week"+Week, url)
-	GameURLs := FindAllBetween(body, "gamelink[^h]*href=\"", "\">")
-	for _, val := range GameURLs {
-		ThisGameLink := FindAllBetween([]byte(val), "/boxscores", ".htm")
-		if ThisGameLink == nil {
-			fmt.Println("Cannot find a game link in ", val)
+	var Games []UpcomingGame
+	for _, Link := range GetGameLinksForWeek(Year, Week) {
+		url := "http://www.pro-football-reference.com" + Link
+		body := CheckFileExists("NFL"+strings.Replace(Link, "/", "-", -1), url)
+		VisitingTeam, HomeTeam = GetTeamNames(string(body))
+		if VisitingTeam == "" {
+			fmt.Println("Error: ", "can't find the teams on the page for", Link)
 			continue
 		}
-		url := "http://www.pro-football-reference.com" + ThisGameLink[0]
-		body := CheckFileExists("NFL"+strings.Replace(ThisGameLink[0], "/", "-", -1), url)
-		VisitingTeam, HomeTeam = GetTeamNames(string(body))
 		Spread := GetSpreadFromProFootballPage(body, VisitingTeam, HomeTeam)
-		TeamData[HomeTeam][SPREAD] = Spread
-		TeamData[VisitingTeam][SPREAD] = -Spread
-		TeamData[HomeTeam][PLAYINGTHISWEEK] = GetTeamFloatFromAbbr(VisitingTeam)
-		TeamData[VisitingTeam][PLAYINGTHISWEEK] = GetTeamFloatFromAbbr(HomeTeam)
+		if Spread == NOSPREAD {
+			continue
+		}
+		Games = append(Games, UpcomingGame{HomeTeam: HomeTeam, VisitingTeam: VisitingTeam, Spread: Spread})
 	}
-	return TeamData
+	return Games
 }
 
 // Given a link in the format "/boxscore/YYYYMMDD0aaa.htm", we find the data for the given game.
 // To save time, we download the html file for later reference.
 func GetDataForGameLink(Link string) (AllTeamData, string, string) {
-	var HomeTeam, VisitingTeam string
-	var StartingPercent, ThisPercent, GuessedSpread, ThisPercentAdjustment float64
-	var err error
-	var TeamData AllTeamData = NewAllTeamData()
-	HaveTeamNames := false
-	url := "http://www.pro-football-reference.com" + Link
-	body := CheckFileExists("NFL"+strings.Replace(Link, "/", "-", -1), url)
-	VisitingTeam, HomeTeam = GetTeamNames(string(body))
-	Data := FindAllBetween(body, "var chartData = ", "\n")
-	if Data == nil {
-		fmt.Println("We didn't find the data we need on the provided page so we can't return anything")
+	Result := GetGameResultForGameLink(Link)
+	if Result == nil {
 		return nil, "", ""
 	}
-	Data[0] = strings.Replace(Data[0], "var chartData = ", "", -1)
-	Data = strings.Split(Data[0][2:len(Data[0])-2], "],[")
-	for _, val := range Data {
-		ThisPlay := strings.Split(val, ",")
-		if !HaveTeamNames {
-			StartingPercent, err = strconv.ParseFloat(ThisPlay[1], 64)
-			if err != nil {
-				fmt.Println("Error: ", err)
-				return nil, "", ""
-			}
-			GuessedSpread = GetSpreadFromProFootballPage(body, VisitingTeam, HomeTeam)
-			TeamData[HomeTeam] = NewTeamData()
-			TeamData[HomeTeam][GAMESPLAYED] = 1.0
-			TeamData[VisitingTeam] = NewTeamData()
-			TeamData[VisitingTeam][GAMESPLAYED] = 1.0
-			HaveTeamNames = true
-		}
-		ThisPercent, err = strconv.ParseFloat(ThisPlay[1], 64)
-		if err != nil {
-			fmt.Println("Error: ", err)
-			return nil, "", ""
-		}
-		ThisPercentAdjustment = FindAdjustedStartingProbability(GuessedSpread, ThisPlay[2], ThisPercentAdjustment)
-		TeamData[HomeTeam][WPADJUST] += ThisPercent - ThisPercentAdjustment
-		TeamData[VisitingTeam][WPADJUST] += ThisPercentAdjustment - ThisPercent
-		TeamData[HomeTeam][STRAIGHTWPADJUST] += ThisPercent - StartingPercent + 0.5
-		TeamData[VisitingTeam][STRAIGHTWPADJUST] += StartingPercent - ThisPercent + 0.5
-	}
-	TeamData[HomeTeam][WPADJUST] /= float64(len(Data))
-	TeamData[VisitingTeam][WPADJUST] /= float64(len(Data))
-	TeamData[HomeTeam][STRAIGHTWPADJUST] /= float64(len(Data))
-	TeamData[VisitingTeam][STRAIGHTWPADJUST] /= float64(len(Data))
-	if ThisPercent == 1.0 {
-		TeamData[HomeTeam][GAMESWON] += 1
-	} else {
-		TeamData[VisitingTeam][GAMESWON] += 1
+	return Result.TeamData(), Result.VisitingTeam, Result.HomeTeam
+}
+
+// Like GetDataForGameLink, but return the whole GameResult.
+// If we incure an error, nil is returned.
+func GetGameResultForGameLink(Link string) *GameResult {
+	return GetGameResultForGameLinkWithMethod(Link, PERPLAY)
+}
+
+// Like GetGameResultForGameLink, but the adjustments are weighted with the given method.
+func GetGameResultForGameLinkWithMethod(Link string, Method AdjustmentMethod) *GameResult {
+	return NFL.GetGameResult(Link, Method)
+}
+
+// Given a year and week number, return the boxscore links for the week's games.
+func GetGameLinksForWeek(Year, Week string) []string {
+	Links, err := NFL.GetGameLinks(Year, Week)
+	if err != nil {
+		fmt.Println("Error: ", err)
 	}
-	return TeamData, VisitingTeam, HomeTeam
+	return Links
 }
 
 // Given a year and week number, returns an AllTeamData with the week's numbers.
 // If we incure an error, nil is returned.
 func GetTeamDataForWeek(TeamData AllTeamData, Year, Week string) {
-	url := "http://www.pro-football-reference.com/years/" + Year + "/week_" + Week + ".htm"
-	body := CheckFileExists("NFL-"+Year+"-Week"+Week, url)
-	GameURLs := FindAllBetween(body, "gamelink[^h]*href=\"", "\">")
-	for _, val := range GameURLs {
-		ThisGameLink := FindAllBetween([]byte(val), "/boxscores", ".htm")
-		if ThisGameLink == nil {
-			fmt.Println("Cannot find a game link in", val)
-			continue
-		}
-		ThisGame, VisitingTeam, HomeTeam := GetDataForGameLink(ThisGameLink[0])
+	for _, Link := range GetGameLinksForWeek(Year, Week) {
+		ThisGame, VisitingTeam, HomeTeam := GetDataForGameLink(Link)
 		if ThisGame == nil {
-			fmt.Println("Error getting game data for link", ThisGameLink[0])
+			fmt.Println("Error getting game data for link", Link)
 			continue
 		}
 		_, ok := TeamData[VisitingTeam]
@@ -355,222 +334,157 @@ func GetTeamDataForWeek(TeamData AllTeamData, Year, Week string) {
 // If StopAtWeek > 0, then we stop gathering data after that week
 // If we incure an error, nil is returned
 func GetTeamDataForYear(Year string, StopAtWeek int) AllTeamData {
-	var TeamData AllTeamData = NewAllTeamData()
-	TeamData["BYE"] = NewTeamData()
-	Week := 1
-	for StopAtWeek < 0 || Week <= StopAtWeek {
-		GetTeamDataForWeek(TeamData, Year, strconv.Itoa(Week))
-		Week++
+	return GetTeamDataForYearWithAggregator(Year, StopAtWeek, CumulativeMean{})
+}
+
+// Like GetTeamDataForYear, but the per-game numbers are combined with the given Aggregator.
+func GetTeamDataForYearWithAggregator(Year string, StopAtWeek int, Agg Aggregator) AllTeamData {
+	Builder := NewSeasonBuilder(Agg)
+	if err := Builder.AddYear(Year, StopAtWeek); err != nil {
+		fmt.Println("Error: ", err)
 	}
-	return TeamData
+	return Builder.TeamData
+}
+
+// The NFL teams in the order of their PLAYINGTHISWEEK codes.
+var nflTeamAbbrs = []string{
+	"BYE", "HTX", "NWE", "CIN", "DEN", "OTI", "RAI", "CRD",
+	"BUF", "RAV", "JAX", "MIA", "CLE", "NYG", "WAS", "GNB",
+	"DET", "CAR", "MIN", "SEA", "SFO", "TAM", "RAM", "PIT",
+	"PHI", "KAN", "NYJ", "CLT", "SDG", "DAL", "CHI", "NOR",
+	"ATL",
+}
+
+// The team names from FootballLocks and the odds files and their pro-football-reference abbreviations.
+var nflTeamNames = map[string]string{
+	"TEXANS":     "HTX",
+	"PATRIOTS":   "NWE",
+	"BENGALS":    "CIN",
+	"BRONCOS":    "DEN",
+	"TITANS":     "OTI",
+	"RAIDERS":    "RAI",
+	"CARDINALS":  "CRD",
+	"BILLS":      "BUF",
+	"RAVENS":     "RAV",
+	"JAGUARS":    "JAX",
+	"DOLPHINS":   "MIA",
+	"BROWNS":     "CLE",
+	"GIANTS":     "NYG",
+	"REDSKINS":   "WAS",
+	"PACKERS":    "GNB",
+	"LIONS":      "DET",
+	"PANTHERS":   "CAR",
+	"VIKINGS":    "MIN",
+	"SEAHAWKS":   "SEA",
+	"49ERS":      "SFO",
+	"BUCCANEERS": "TAM",
+	"RAMS":       "RAM",
+	"STEELERS":   "PIT",
+	"EAGLES":     "PHI",
+	"CHIEFS":     "KAN",
+	"JETS":       "NYJ",
+	"COLTS":      "CLT",
+	"CHARGERS":   "SDG",
+	"COWBOYS":    "DAL",
+	"BEARS":      "CHI",
+	"SAINTS":     "NOR",
+	"FALCONS":    "ATL",
 }
 
 // Translate team names from FootballLocks to pro-football-reference.
 func GetPFRTeamAbbr(TeamName string) string {
-	return map[string]string{
-		"TEXANS":     "HTX",
-		"PATRIOTS":   "NWE",
-		"BENGALS":    "CIN",
-		"BRONCOS":    "DEN",
-		"TITANS":     "OTI",
-		"RAIDERS":    "RAI",
-		"CARDINALS":  "CRD",
-		"BILLS":      "BUF",
-		"RAVENS":     "RAV",
-		"JAGUARS":    "JAX",
-		"DOLPHINS":   "MIA",
-		"BROWNS":     "CLE",
-		"GIANTS":     "NYG",
-		"REDSKINS":   "WAS",
-		"PACKERS":    "GNB",
-		"LIONS":      "DET",
-		"PANTHERS":   "CAR",
-		"VIKINGS":    "MIN",
-		"SEAHAWKS":   "SEA",
-		"49ERS":      "SFO",
-		"BUCCANEERS": "TAM",
-		"RAMS":       "RAM",
-		"STEELERS":   "PIT",
-		"EAGLES":     "PHI",
-		"CHIEFS":     "KAN",
-		"JETS":       "NYJ",
-		"COLTS":      "CLT",
-		"CHARGERS":   "SDG",
-		"COWBOYS":    "DAL",
-		"BEARS":      "CHI",
-		"SAINTS":     "NOR",
-		"FALCONS":    "ATL",
-	}[TeamName]
+	return NFL.Teams.FromName(TeamName)
 }
 
 // This function returns a float for storage in the TeamData type
 func GetTeamAbbrFromFloat(Index float64) string {
-	return map[float64]string{
-		0:  "BYE",
-		1:  "HTX",
-		2:  "NWE",
-		3:  "CIN",
-		4:  "DEN",
-		5:  "OTI",
-		6:  "RAI",
-		7:  "CRD",
-		8:  "BUF",
-		9:  "RAV",
-		10: "JAX",
-		11: "MIA",
-		12: "CLE",
-		13: "NYG",
-		14: "WAS",
-		15: "GNB",
-		16: "DET",
-		17: "CAR",
-		18: "MIN",
-		19: "SEA",
-		20: "SFO",
-		21: "TAM",
-		22: "RAM",
-		23: "PIT",
-		24: "PHI",
-		25: "KAN",
-		26: "NYJ",
-		27: "CLT",
-		28: "SDG",
-		29: "DAL",
-		30: "CHI",
-		31: "NOR",
-		32: "ATL",
-	}[Index]
+	return NFL.Teams.Abbr(Index)
 }
 
 // This function returns the team abbreviation from a float64 stored in the TeamData type
 func GetTeamFloatFromAbbr(Abbr string) float64 {
-	return map[string]float64{
-		"BYE": 0,
-		"HTX": 1,
-		"NWE": 2,
-		"CIN": 3,
-		"DEN": 4,
-		"OTI": 5,
-		"RAI": 6,
-		"CRD": 7,
-		"BUF": 8,
-		"RAV": 9,
-		"JAX": 10,
-		"MIA": 11,
-		"CLE": 12,
-		"NYG": 13,
-		"WAS": 14,
-		"GNB": 15,
-		"DET": 16,
-		"CAR": 17,
-		"MIN": 18,
-		"SEA": 19,
-		"SFO": 20,
-		"TAM": 21,
-		"RAM": 22,
-		"PIT": 23,
-		"PHI": 24,
-		"KAN": 25,
-		"NYJ": 26,
-		"CLT": 27,
-		"SDG": 28,
-		"DAL": 29,
-		"CHI": 30,
-		"NOR": 31,
-		"ATL": 32,
-	}[Abbr]
+	return NFL.Teams.Float(Abbr)
 }
 
 // This takes the spread information I scraped from scoresandodds.com and
-// creates data to use with a machine learning algorithm
+// creates data to use with a machine learning algorithm.
+// The dataset package builds the same kind of data with a header, labels and more features.
 func CreateDataFromSpreadFiles(Sport string) {
+	CreateDataFromSpreadFilesWithAggregator(Sport, CumulativeMean{})
+}
+
+// Like CreateDataFromSpreadFiles, but the per-game numbers are combined with the given Aggregator.
+func CreateDataFromSpreadFilesWithAggregator(Sport string, Agg Aggregator) {
+	createDataFromSpreadFiles(Sport, Agg, nil)
+}
+
+// Like CreateDataFromSpreadFiles, but the teams' WPADJUST is shrunk toward the Shrinkage's priors, so there is a row
+// for every game from week 1 on. Each row is the ShrinkagePredictor's spread, the spread and the label, and
+// is written to "<Sport>ShrinkageWPData.txt". Each season's data becomes the LastSeason of the one after it.
+func CreateDataFromSpreadFilesWithShrinkage(Sport string, Prior *Shrinkage) {
+	createDataFromSpreadFiles(Sport, nil, Prior)
+}
+
+func createDataFromSpreadFiles(Sport string, Agg Aggregator, Prior *Shrinkage) {
 	YearToStart := 2015
 	YearToStop := 2015
-	FileToWrite, _ := os.Create(Sport + "WPData.txt")
+	ThisSport, ok := GetSport(Sport)
+	if !ok {
+		fmt.Printf("ERROR: we don't know the sport %v\n", Sport)
+		return
+	}
+	FileName := Sport + "WPData.txt"
+	var Predictors []Predictor
+	if Prior != nil {
+		// Copy the Shrinkage so moving LastSeason along doesn't change the caller's.
+		ThisPrior := *Prior
+		Prior = &ThisPrior
+		Agg = Prior
+		FileName = Sport + "ShrinkageWPData.txt"
+		Predictors = []Predictor{ShrinkagePredictor{Shrinkage: Prior, Sport: ThisSport}}
+	} else {
+		Predictors = append(DefaultPredictors(), NewEnsemblePredictor())
+	}
+	FileToWrite, _ := os.Create(FileName)
 	defer FileToWrite.Close()
 	for YearToStart <= YearToStop {
 		fmt.Printf("Now compiling stats for %v year...\n", YearToStart)
-		var TeamData AllTeamData = NewAllTeamData()
-		if strings.Compare(Sport, "Football") == 0 {
-			TeamData["BYE"] = NewTeamData()
-		}
-		file, err := os.Open(strconv.Itoa(YearToStart) + Sport + "OddsAndScores.txt")
+		Builder := NewSeasonBuilderForSport(Agg, ThisSport)
+		TeamData := Builder.TeamData
+		// The games come back with their weeks, so the season keeps them apart like SeasonBuilder.AddWeek does.
+		Games, err := ThisSport.LoadGamesFromSpreadFile(YearToStart)
 		if err != nil {
 			fmt.Printf("ERROR: error reading file for year %v and sport %v\n", YearToStart, Sport)
-			file.Close()
 			return
 		}
-		scan := bufio.NewScanner(file)
-		for scan.Scan() {
-			Games := strings.Split(scan.Text(), ",")
-			DateString := Games[0]
-			Games = Games[1 : len(Games)-1]
-			for _, val := range Games {
-				GameData := strings.Split(val, " ")
-				if len(GameData) < 7 {
-					continue
+		for _, Game := range Games {
+			Result := Game.GameResult
+			HomeTeam, VisitingTeam, Spread := Result.HomeTeam, Result.VisitingTeam, Result.Spread
+			_, ok := TeamData[HomeTeam]
+			_, ok2 := TeamData[VisitingTeam]
+			if Prior != nil || (ok && ok2 && TeamData[HomeTeam][GAMESPLAYED] > 2) {
+				// The predictors see this game's line, the same way Predict gives it to them.
+				GameData := TeamData.ForGameForSport(UpcomingGame{HomeTeam: HomeTeam, VisitingTeam: VisitingTeam, Spread: Spread}, ThisSport)
+				for _, Guess := range Predictors {
+					FileToWrite.Write([]byte(strconv.FormatFloat(Guess.Predict(GameData, HomeTeam, VisitingTeam).Spread, 'f', -1, 64)))
+					FileToWrite.Write([]byte(","))
 				}
-				HomeTeam := GetPFRTeamAbbr(GameData[3])
-				VisitingScore, _ := strconv.ParseFloat(GameData[2], 64)
-				HomeScore, _ := strconv.ParseFloat(GameData[5], 64)
-				Spread, _ := strconv.ParseFloat(GameData[1], 64)
-				if Spread < 0 {
-					Spread = -Spread
+				FileToWrite.Write([]byte(strconv.FormatFloat(Spread, 'f', -1, 64)))
+				FileToWrite.Write([]byte(","))
+				if Result.HomeScore-Result.VisitingScore+Spread > 0 {
+					FileToWrite.Write([]byte("1"))
+				} else if Result.HomeScore-Result.VisitingScore+Spread < 0 {
+					FileToWrite.Write([]byte("0"))
+				} else {
+					FileToWrite.Write([]byte("2"))
 				}
-				if Spread < -60 || Spread > 60 {
-					Spread, _ = strconv.ParseFloat(GameData[4], 64)
-					if Spread > 0 {
-						Spread = -Spread
-					}
-				}
-				//StartingWP := WinProbability(0, Spread, STDDEV)
-				ThisGame, VisitingTeam, _ := GetDataForGameLink("/boxscores/" + DateString + "0" + strings.ToLower(HomeTeam) + ".htm")
-				if ThisGame == nil {
-					fmt.Println("Error getting game data for link", DateString+strings.ToLower(HomeTeam)+".htm")
-					continue
-				}
-				if _, ok := TeamData[HomeTeam]; ok {
-					if TeamData[HomeTeam][GAMESPLAYED] > 2 {
-						GuessSpread := TeamData[HomeTeam][STRAIGHTWPADJUST]/TeamData[HomeTeam][GAMESPLAYED] - TeamData[VisitingTeam][STRAIGHTWPADJUST]/TeamData[VisitingTeam][GAMESPLAYED]
-						GuessOP := (-TeamData[HomeTeam][OPPWPADJUST]/(TeamData[HomeTeam][GAMESPLAYED]-1) + TeamData[VisitingTeam][OPPWPADJUST]/(TeamData[VisitingTeam][GAMESPLAYED]-1)) / 2
-						GuessWP := (-TeamData[VisitingTeam][WPADJUST]/TeamData[VisitingTeam][GAMESPLAYED] + TeamData[HomeTeam][WPADJUST]/TeamData[HomeTeam][GAMESPLAYED]) / 2
-						GuessBoth := (GuessWP + GuessOP) / 2.0
-						GuessWP = NewSpread(0.5+GuessWP+GuessSpread, 0.0, STDDEV)
-						GuessOP = NewSpread(0.5+GuessOP+GuessSpread, 0.0, STDDEV)
-						GuessBoth = NewSpread(0.5+GuessBoth+GuessSpread, 0.0, STDDEV)
-						GuessSpread = NewSpread(0.5+GuessSpread, 0.0, STDDEV)
-						NewProb := WinProbability(0, TeamData[HomeTeam][SPREAD], STDDEV) + ((TeamData[HomeTeam][WPADJUST]/TeamData[HomeTeam][GAMESPLAYED])-(TeamData[VisitingTeam][WPADJUST]/TeamData[VisitingTeam][GAMESPLAYED]))/2
-						EstSpread := NewSpread(NewProb, TeamData[HomeTeam][SPREAD], STDDEV)
-						FileToWrite.Write([]byte(strconv.FormatFloat(GuessSpread, 'f', -1, 64)))
-						FileToWrite.Write([]byte(","))
-						FileToWrite.Write([]byte(strconv.FormatFloat(GuessWP, 'f', -1, 64)))
-						FileToWrite.Write([]byte(","))
-						FileToWrite.Write([]byte(strconv.FormatFloat(GuessOP, 'f', -1, 64)))
-						FileToWrite.Write([]byte(","))
-						FileToWrite.Write([]byte(strconv.FormatFloat(GuessBoth, 'f', -1, 64)))
-						FileToWrite.Write([]byte(","))
-						FileToWrite.Write([]byte(strconv.FormatFloat(EstSpread, 'f', -1, 64)))
-						FileToWrite.Write([]byte(","))
-						FileToWrite.Write([]byte(strconv.FormatFloat((GuessSpread+GuessWP+GuessOP+GuessBoth+EstSpread)/5, 'f', -1, 64)))
-						FileToWrite.Write([]byte(","))
-						FileToWrite.Write([]byte(strconv.FormatFloat(Spread, 'f', -1, 64)))
-						FileToWrite.Write([]byte(","))
-						if HomeScore-VisitingScore+Spread > 0 {
-							FileToWrite.Write([]byte("1"))
-						} else if HomeScore-VisitingScore+Spread < 0 {
-							FileToWrite.Write([]byte("0"))
-						} else {
-							FileToWrite.Write([]byte("2"))
-						}
-						FileToWrite.Write([]byte("\n"))
-					}
-					ThisGame[VisitingTeam][OPPWPADJUST] += TeamData[HomeTeam][WPADJUST] / TeamData[HomeTeam][GAMESPLAYED]
-					ThisGame[HomeTeam][OPPWPADJUST] += TeamData[VisitingTeam][WPADJUST] / TeamData[VisitingTeam][GAMESPLAYED]
-				}
-				TeamData.AddData(ThisGame)
+				FileToWrite.Write([]byte("\n"))
 			}
+			Builder.AddGameResult(Result)
+		}
+		if Prior != nil {
+			Prior.LastSeason = Builder.TeamData
 		}
-		file.Close()
 		YearToStart++
 	}
 }
@@ -578,45 +492,15 @@ func CreateDataFromSpreadFiles(Sport string) {
 // Given a completed AllTeamVariable, we add the current betting lines from FootballLocks
 // and calculate the win probability.
 func GetCurrentSpreadsAndWinProb(TeamData AllTeamData) AllTeamData {
-	url := "https://fantasydata.com/nfl-stats/nfl-point-spreads-and-odds.aspx"
-	response, err := http.Get(url)
-	defer response.Body.Close()
-	if err != nil {
-		fmt.Println("Error: ", err)
-		return nil
-	}
-	body, err := ioutil.ReadAll(response.Body)
+	return GetCurrentSpreadsFromProvider(TeamData, FantasyDataProvider{})
+}
+
+// Like GetCurrentSpreadsAndWinProb, but the lines come from the given LineProvider.
+func GetCurrentSpreadsFromProvider(TeamData AllTeamData, Provider LineProvider) AllTeamData {
+	Games, err := Provider.Lines()
 	if err != nil {
 		fmt.Println("Error: ", err)
 		return nil
 	}
-	Index := bytes.Index(body, []byte("StatsGrid"))
-	body = body[Index:]
-	Index = bytes.Index(body, []byte("<tbody>"))
-	body = body[Index:]
-	Index = bytes.Index(body, []byte("</tbody>"))
-	body = body[:Index]
-	TableData := FindAllBetween(body, "<td>", "</td>")
-	for i := 0; i < len(TableData); i += 6 {
-		Favorite := strings.Replace(string(TableData[i]), "at ", "", 1)
-		Dog := strings.Replace(string(TableData[i+2]), "at ", "", 1)
-		Favorite = strings.Replace(Favorite, "<td>", "", 1)
-		Favorite = strings.Replace(Favorite, "</td>", "", 1)
-		Favorite = GetPFRTeamAbbr(strings.ToUpper(Favorite))
-		Dog = strings.Replace(Dog, "<td>", "", 1)
-		Dog = strings.Replace(Dog, "</td>", "", 1)
-		Dog = GetPFRTeamAbbr(strings.ToUpper(Dog))
-		TableData[i+1] = strings.Replace(TableData[i+1], "<td>", "", 1)
-		TableData[i+1] = strings.Replace(TableData[i+1], "</td>", "", 1)
-		Spread, err := strconv.ParseFloat(TableData[i+1], 64)
-		if err != nil {
-			fmt.Printf("It seems that the line for the %v vs %v game is not available because we got %v for the line.\n", Favorite, Dog, TableData[i+1])
-		} else {
-			TeamData[Favorite][SPREAD] = Spread
-			TeamData[Dog][SPREAD] = -Spread
-			TeamData[Favorite][PLAYINGTHISWEEK] = GetTeamFloatFromAbbr(Dog)
-			TeamData[Dog][PLAYINGTHISWEEK] = GetTeamFloatFromAbbr(Favorite)
-		}
-	}
-	return TeamData
+	return TeamData.AddUpcomingGames(Games)
 }
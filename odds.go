@@ -0,0 +1,211 @@
+package nflwp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An OddsRecord is one game from a scoresandodds file.
+// Each line of the file is "YYYYMMDD,<game>,<game>,...," and each game is
+// "<visiting team> <visiting line> <visiting score> <home team> <home line> <home score> ...".
+// One of the lines is the spread for the favorite and the other is the over/under.
+// Spread is from the home team's point of view, so it is negative when the home team is favored.
+// Favorite is "" for a pick'em. HomeTeam and VisitingTeam are the NFL abbreviations and are "" if the name isn't known;
+// other sports look the names up in their own TeamRegistry.
+type OddsRecord struct {
+	Line          int
+	Date          string
+	HomeName      string
+	VisitingName  string
+	HomeTeam      string
+	VisitingTeam  string
+	HomeScore     float64
+	VisitingScore float64
+	Spread        float64
+	Total         float64
+	Favorite      string
+}
+
+// An OddsParseError is a game in an odds file that couldn't be read.
+type OddsParseError struct {
+	Line int
+	Game string
+	Err  error
+}
+
+func (e *OddsParseError) Error() string {
+	if e.Game == "" {
+		return fmt.Sprintf("line %v: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %v: game %q: %v", e.Line, e.Game, e.Err)
+}
+
+func (e *OddsParseError) Unwrap() error {
+	return e.Err
+}
+
+// An OddsReader reads OddsRecords from a scoresandodds file.
+type OddsReader struct {
+	scan    *bufio.Scanner
+	line    int
+	pending []OddsRecord
+	errs    []error
+}
+
+// Make an OddsReader reading from r.
+func NewOddsReader(r io.Reader) *OddsReader {
+	return &OddsReader{scan: bufio.NewScanner(r)}
+}
+
+// Open the odds file "<Year><Sport>OddsAndScores.txt" and read all of its records.
+func ReadOddsFile(Sport string, Year int) ([]OddsRecord, error) {
+	file, err := os.Open(strconv.Itoa(Year) + Sport + "OddsAndScores.txt")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return NewOddsReader(file).ReadAll()
+}
+
+// Read the next game from the file. It returns io.EOF when there are no more games.
+// A game that can't be parsed returns an *OddsParseError, and the next call moves on to the game after it,
+// so the caller can decide whether to skip bad games or give up.
+func (o *OddsReader) Read() (OddsRecord, error) {
+	for len(o.pending) == 0 && len(o.errs) == 0 {
+		if !o.scan.Scan() {
+			if err := o.scan.Err(); err != nil {
+				return OddsRecord{}, err
+			}
+			return OddsRecord{}, io.EOF
+		}
+		o.line++
+		o.parseLine(o.scan.Text())
+	}
+	if len(o.errs) > 0 {
+		err := o.errs[0]
+		o.errs = o.errs[1:]
+		return OddsRecord{}, err
+	}
+	Record := o.pending[0]
+	o.pending = o.pending[1:]
+	return Record, nil
+}
+
+// Read every game left in the file, stopping at the first error.
+func (o *OddsReader) ReadAll() ([]OddsRecord, error) {
+	var Records []OddsRecord
+	for {
+		Record, err := o.Read()
+		if err == io.EOF {
+			return Records, nil
+		}
+		if err != nil {
+			return Records, err
+		}
+		Records = append(Records, Record)
+	}
+}
+
+// Parse a line of the file into pending records and errors.
+func (o *OddsReader) parseLine(Text string) {
+	if strings.TrimSpace(Text) == "" {
+		return
+	}
+	Fields := strings.Split(Text, ",")
+	Date := Fields[0]
+	if _, err := time.Parse("20060102", Date); err != nil {
+		o.errs = append(o.errs, &OddsParseError{Line: o.line, Err: fmt.Errorf("bad date %q", Date)})
+		return
+	}
+	for _, val := range Fields[1:] {
+		if strings.TrimSpace(val) == "" {
+			continue
+		}
+		Record, err := ParseOddsGame(val)
+		if err != nil {
+			o.errs = append(o.errs, &OddsParseError{Line: o.line, Game: val, Err: err})
+			continue
+		}
+		Record.Line = o.line
+		Record.Date = Date
+		o.pending = append(o.pending, Record)
+	}
+}
+
+// Given a game from an odds file, like "PATRIOTS -7 28 STEELERS 51 21 F", parse it into an OddsRecord.
+// Each team's name runs up to its line, so names like "OHIO STATE" can have more than one word.
+// The line with the smaller magnitude is taken as the spread and the team it belongs to as the favorite;
+// the other line is the total. "PK" is a pick'em. Date and Line are left for the caller to fill in.
+func ParseOddsGame(Game string) (OddsRecord, error) {
+	var Record OddsRecord
+	var err error
+	GameData := strings.Fields(Game)
+	VisitingName, VisitingLineText, VisitingScoreText, GameData := splitOddsTeam(GameData)
+	HomeName, HomeLineText, HomeScoreText, _ := splitOddsTeam(GameData)
+	if VisitingName == "" || HomeName == "" || HomeScoreText == "" {
+		return Record, fmt.Errorf("expected two teams, each with a line and a score, in %q", Game)
+	}
+	Record.VisitingName = VisitingName
+	Record.HomeName = HomeName
+	Record.VisitingTeam = GetPFRTeamAbbr(Record.VisitingName)
+	Record.HomeTeam = GetPFRTeamAbbr(Record.HomeName)
+	if Record.VisitingScore, err = strconv.ParseFloat(VisitingScoreText, 64); err != nil {
+		return Record, fmt.Errorf("bad visiting score %q", VisitingScoreText)
+	}
+	if Record.HomeScore, err = strconv.ParseFloat(HomeScoreText, 64); err != nil {
+		return Record, fmt.Errorf("bad home score %q", HomeScoreText)
+	}
+	VisitingLine, _ := parseOddsLine(VisitingLineText)
+	HomeLine, _ := parseOddsLine(HomeLineText)
+	VisitingLine, HomeLine = math.Abs(VisitingLine), math.Abs(HomeLine)
+	switch {
+	case VisitingLine == HomeLine:
+		return Record, fmt.Errorf("can't tell the spread from the total in %v and %v", VisitingLineText, HomeLineText)
+	case VisitingLine < HomeLine:
+		Record.Spread = VisitingLine
+		Record.Total = HomeLine
+		Record.Favorite = Record.VisitingName
+	default:
+		Record.Spread = -HomeLine
+		Record.Total = VisitingLine
+		Record.Favorite = Record.HomeName
+	}
+	if Record.Spread == 0 {
+		Record.Favorite = ""
+	}
+	return Record, nil
+}
+
+// Split the first team off the fields of a game: its name is every field before the first one that reads as a line,
+// and the score is the field after the line. The name is empty if there is no line after it.
+func splitOddsTeam(Fields []string) (string, string, string, []string) {
+	for i := 1; i < len(Fields); i++ {
+		if _, err := parseOddsLine(Fields[i]); err != nil {
+			continue
+		}
+		if i+1 == len(Fields) {
+			return strings.Join(Fields[:i], " "), Fields[i], "", nil
+		}
+		return strings.Join(Fields[:i], " "), Fields[i], Fields[i+1], Fields[i+2:]
+	}
+	return "", "", "", nil
+}
+
+// Parse a line from an odds file, where "PK" means a pick'em.
+func parseOddsLine(Line string) (float64, error) {
+	if strings.EqualFold(Line, "PK") {
+		return 0, nil
+	}
+	return strconv.ParseFloat(Line, 64)
+}
+
+// The pro-football-reference link for the game. Use Sport.GameLink for other sports.
+func (r OddsRecord) GameLink() string {
+	return NFL.Source.GamePath(r.Date, r.HomeTeam)
+}
@@ -0,0 +1,80 @@
+package nflwp
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseOddsGame(t *testing.T) {
+	games := []string{
+		"PATRIOTS 51 28 STEELERS -7 21 F",
+		"BILLS -3 27 COLTS 44.5 14 F",
+		"JETS 41 20 BROWNS PK 17 F",
+		"VIRGINIA TECH 57 24 OHIO STATE -14 42 F",
+	}
+	expectedSpreads := []float64{-7, 3, 0, -14}
+	expectedTotals := []float64{51, 44.5, 41, 57}
+	expectedFavorites := []string{"STEELERS", "BILLS", "", "OHIO STATE"}
+	for i := 0; i < len(games); i++ {
+		record, err := ParseOddsGame(games[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.Spread != expectedSpreads[i] || record.Total != expectedTotals[i] || record.Favorite != expectedFavorites[i] {
+			t.Errorf("We got an unexpected result: %v %v %v instead of %v %v %v", record.Spread, record.Total, record.Favorite, expectedSpreads[i], expectedTotals[i], expectedFavorites[i])
+		}
+	}
+	record, _ := ParseOddsGame(games[0])
+	if record.HomeTeam != "PIT" || record.VisitingTeam != "NWE" || record.HomeScore != 21 || record.VisitingScore != 28 {
+		t.Errorf("We got an unexpected result: %v", record)
+	}
+	record, _ = ParseOddsGame(games[3])
+	if record.VisitingName != "VIRGINIA TECH" || record.HomeName != "OHIO STATE" || record.HomeScore != 42 || record.VisitingScore != 24 {
+		t.Errorf("We got an unexpected result: %v", record)
+	}
+	for _, val := range []string{"PATRIOTS 51 28 STEELERS", "PATRIOTS 51 xx STEELERS -7 21 F", "PATRIOTS 7 28 STEELERS -7 21 F"} {
+		if _, err := ParseOddsGame(val); err == nil {
+			t.Errorf("We expected an error for %q", val)
+		}
+	}
+}
+
+func TestOddsReader(t *testing.T) {
+	file := "20150910,PATRIOTS 51 28 STEELERS -7 21 F,\n" +
+		"20150913,BILLS -3 27 COLTS 44.5 14 F,JETS 41 2x BROWNS -3 17 F,\n" +
+		"\n" +
+		"2015091,BILLS -3 27 COLTS 44.5 14 F,\n" +
+		"20150920,RAMS -1 10 SEAHAWKS 40 31 F,\n"
+	reader := NewOddsReader(strings.NewReader(file))
+	var records []OddsRecord
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *OddsParseError
+		if errors.As(err, &parseErr) {
+			lines = append(lines, parseErr.Line)
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 3 || records[1].Date != "20150913" || records[1].Line != 2 || records[2].Line != 5 {
+		t.Errorf("We got an unexpected result: %v", records)
+	}
+	if len(lines) != 2 || lines[0] != 2 || lines[1] != 4 {
+		t.Errorf("We got an unexpected result: %v instead of [2 4]", lines)
+	}
+	if records[2].GameLink() != "/boxscores/201509200sea.htm" {
+		t.Errorf("We got an unexpected result: %v", records[2].GameLink())
+	}
+	if _, err := NewOddsReader(strings.NewReader(file)).ReadAll(); err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("We got an unexpected result: %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package nflwp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The columns of an odds table. Columns that aren't in the table are -1.
+type oddsColumns struct {
+	Kickoff, Favorite, Spread, Underdog, Total, FavoriteMoneyline, UnderdogMoneyline int
+}
+
+// The fantasydata.com columns, used when a table has no header row.
+var defaultOddsColumns = oddsColumns{Kickoff: -1, Favorite: 0, Spread: 1, Underdog: 2, Total: 3, FavoriteMoneyline: 4, UnderdogMoneyline: 5}
+
+// An OddsRowError is a row of an odds table that couldn't be turned into a game.
+// Row counts every row of the table from 1, header included.
+type OddsRowError struct {
+	Row   int
+	Cells []string
+	Err   error
+}
+
+func (e *OddsRowError) Error() string {
+	return fmt.Sprintf("row %v %q: %v", e.Row, e.Cells, e.Err)
+}
+
+func (e *OddsRowError) Unwrap() error {
+	return e.Err
+}
+
+// Find the columns from a header row, or return false if the row isn't a header.
+// A header has to name both a favorite and an underdog column.
+func findOddsColumns(Cells []string) (oddsColumns, bool) {
+	Columns := oddsColumns{-1, -1, -1, -1, -1, -1, -1}
+	for i, val := range Cells {
+		Name := strings.ToLower(val)
+		Money := strings.Contains(Name, "money") || strings.HasSuffix(Name, " ml")
+		switch {
+		case Money && strings.Contains(Name, "fav"):
+			Columns.FavoriteMoneyline = i
+		case Money && strings.Contains(Name, "dog"):
+			Columns.UnderdogMoneyline = i
+		case Money:
+		case strings.Contains(Name, "favorite"):
+			Columns.Favorite = i
+		case strings.Contains(Name, "underdog"):
+			Columns.Underdog = i
+		case strings.Contains(Name, "total") || strings.Contains(Name, "over/under") || strings.Contains(Name, "o/u"):
+			Columns.Total = i
+		case strings.Contains(Name, "spread") || strings.Contains(Name, "line"):
+			Columns.Spread = i
+		case strings.Contains(Name, "date") || strings.Contains(Name, "time") || strings.Contains(Name, "kickoff"):
+			Columns.Kickoff = i
+		}
+	}
+	return Columns, Columns.Favorite >= 0 && Columns.Underdog >= 0 && Columns.Spread >= 0
+}
+
+// The number of cells a row needs to have every column.
+func (c oddsColumns) width() int {
+	Width := 0
+	for _, val := range []int{c.Kickoff, c.Favorite, c.Spread, c.Underdog, c.Total, c.FavoriteMoneyline, c.UnderdogMoneyline} {
+		if val+1 > Width {
+			Width = val + 1
+		}
+	}
+	return Width
+}
+
+// Given the HTML of an odds table, parse each row into an UpcomingGame with Source set to the given source.
+// The columns come from the header row, which has to name the favorite, spread and underdog and can also
+// name the kickoff, total and moneylines. Rows before the header are skipped, and a table without a header
+// is read with fantasydata.com's columns.
+// The home team is the one marked with "at " or "@". Rows that can't be read, like games without a line yet,
+// unknown teams, kickoffs we can't read or rows where neither or both teams are marked, are returned as
+// *OddsRowErrors instead of games.
+// Kickoffs are read with ParseKickoff, so AssumePM is whether a kickoff without AM or PM is in the afternoon or evening.
+func ParseOddsTable(body []byte, Source string, Reference time.Time, AssumePM bool) ([]UpcomingGame, []error) {
+	var Games []UpcomingGame
+	var Errors []error
+	Rows := tableRows(body)
+	Columns, Start := defaultOddsColumns, 0
+	for i, Cells := range Rows {
+		if Header, ok := findOddsColumns(Cells); ok {
+			Columns, Start = Header, i+1
+			break
+		}
+	}
+	for i := Start; i < len(Rows); i++ {
+		Cells := Rows[i]
+		Game, err := parseOddsRow(Cells, Columns, Reference, AssumePM)
+		if err != nil {
+			Errors = append(Errors, &OddsRowError{Row: i + 1, Cells: Cells, Err: err})
+			continue
+		}
+		Game.Source = Source
+		Games = append(Games, Game)
+	}
+	return Games, Errors
+}
+
+func parseOddsRow(Cells []string, Columns oddsColumns, Reference time.Time, AssumePM bool) (UpcomingGame, error) {
+	if len(Cells) < Columns.width() {
+		return UpcomingGame{}, fmt.Errorf("expected %v cells, got %v", Columns.width(), len(Cells))
+	}
+	Spread, err := parseLine(Cells[Columns.Spread])
+	if err != nil {
+		return UpcomingGame{}, fmt.Errorf("no line yet, got %q", Cells[Columns.Spread])
+	}
+	Game, err := gameFromFavorite(Cells[Columns.Favorite], Cells[Columns.Underdog], Spread)
+	if err != nil {
+		return UpcomingGame{}, err
+	}
+	if Columns.Total >= 0 {
+		Game.Total, _ = parseLine(Cells[Columns.Total])
+	}
+	if Columns.FavoriteMoneyline >= 0 && Columns.UnderdogMoneyline >= 0 {
+		Game.HomeMoneyline, Game.VisitingMoneyline = moneylines(Cells[Columns.Favorite], Cells[Columns.FavoriteMoneyline], Cells[Columns.UnderdogMoneyline])
+	}
+	if Columns.Kickoff >= 0 && strings.TrimSpace(Cells[Columns.Kickoff]) != "" {
+		if Game.Kickoff, err = ParseKickoff(Cells[Columns.Kickoff], Reference, AssumePM); err != nil {
+			return UpcomingGame{}, err
+		}
+	}
+	return Game, nil
+}
+
+// The layouts ParseKickoff tries after taking off the day of the week and the time zone.
+// Layouts with PM read AM as well. Layouts with 3 and without PM have no AM or PM, so the hour could be either.
+var kickoffLayouts = []string{
+	"1/2/2006 3:04 PM", "1/2/2006 3:04PM", "1/2/2006 3:04", "1/2/2006 15:04",
+	"1/2 3:04 PM", "1/2 3:04PM", "1/2 3:04", "1/2 15:04",
+	"Jan 2, 2006 3:04 PM", "Jan 2 3:04 PM",
+}
+
+// Given a kickoff from an odds page, like "Thu 9/10 8:30 PM ET" or "9/13 1:00 ET", return the time.
+// Times are Eastern. A time without AM or PM from 1:00 to 12:59 could be either, so it is an error
+// unless AssumePM is true, which puts it in the afternoon or evening; 0:00 and 13:00 on are 24 hour times.
+// RFC 3339 times are read as they are. Without a year, the kickoff goes in the year that puts it closest to Reference.
+func ParseKickoff(Text string, Reference time.Time, AssumePM bool) (time.Time, error) {
+	Text = strings.TrimSpace(Text)
+	if Kickoff, err := time.Parse(time.RFC3339, Text); err == nil {
+		return Kickoff, nil
+	}
+	Fields := strings.Fields(Text)
+	if len(Fields) > 0 && len(Fields[0]) == 3 && !strings.Contains(Fields[0], "/") {
+		if _, err := time.Parse("Mon", Fields[0]); err == nil {
+			Fields = Fields[1:]
+		}
+	}
+	if len(Fields) > 0 {
+		switch strings.ToUpper(Fields[len(Fields)-1]) {
+		case "ET", "EST", "EDT":
+			Fields = Fields[:len(Fields)-1]
+		}
+	}
+	Text = strings.Join(Fields, " ")
+	Eastern := easternTime()
+	for _, Layout := range kickoffLayouts {
+		Kickoff, err := time.ParseInLocation(Layout, Text, Eastern)
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(Layout, "PM") && !strings.Contains(Layout, "15") && Kickoff.Hour() >= 1 {
+			if !AssumePM {
+				return time.Time{}, fmt.Errorf("can't tell if the kickoff %q is AM or PM", Text)
+			}
+			if Kickoff.Hour() < 12 {
+				Kickoff = Kickoff.Add(12 * time.Hour)
+			}
+		}
+		if !strings.Contains(Layout, "2006") {
+			Kickoff = closestYear(Kickoff, Reference, Eastern)
+		}
+		return Kickoff, nil
+	}
+	return time.Time{}, fmt.Errorf("can't read the kickoff %q", Text)
+}
+
+// A kickoff without a year comes back in year 0, so move it to the year closest to Reference.
+func closestYear(Kickoff, Reference time.Time, Location *time.Location) time.Time {
+	Best := time.Time{}
+	for _, Year := range []int{Reference.Year() - 1, Reference.Year(), Reference.Year() + 1} {
+		Try := time.Date(Year, Kickoff.Month(), Kickoff.Day(), Kickoff.Hour(), Kickoff.Minute(), 0, 0, Location)
+		if Best.IsZero() || absDuration(Try.Sub(Reference)) < absDuration(Best.Sub(Reference)) {
+			Best = Try
+		}
+	}
+	return Best
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// US Eastern time, or a fixed UTC-5 if the time zone database isn't there.
+func easternTime() *time.Location {
+	if Location, err := time.LoadLocation("America/New_York"); err == nil {
+		return Location
+	}
+	return time.FixedZone("EST", -5*3600)
+}
+
+// The team favored in the game, or "" for a pick'em.
+func (g UpcomingGame) Favorite() string {
+	if g.Spread < 0 {
+		return g.HomeTeam
+	} else if g.Spread > 0 {
+		return g.VisitingTeam
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package nflwp
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestParseOddsTable(t *testing.T) {
+	body, err := ioutil.ReadFile("testdata/oddstable.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	games, errs := ParseOddsTable(body, "test", week1, true)
+	checkGames(t, games, []UpcomingGame{
+		{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -7, Total: 51, HomeMoneyline: -300, VisitingMoneyline: 250, Kickoff: thursday, Source: "test"},
+		{HomeTeam: "CHI", VisitingTeam: "GNB", Spread: 6.5, Total: 48, HomeMoneyline: 230, VisitingMoneyline: -280, Kickoff: sunday, Source: "test"},
+		{HomeTeam: "MIN", VisitingTeam: "SFO", Spread: 0, Total: 42.5, HomeMoneyline: -125, VisitingMoneyline: 105, Kickoff: time.Date(2015, 9, 15, 2, 20, 0, 0, time.UTC), Source: "test"},
+	})
+	// The game without a line, the unknown team, the game where neither team is marked and the short row.
+	expectedRows := []int{5, 6, 7, 8}
+	if len(errs) != len(expectedRows) {
+		t.Fatalf("We got an unexpected result: %v", errs)
+	}
+	for i, err := range errs {
+		var rowErr *OddsRowError
+		if !errors.As(err, &rowErr) || rowErr.Row != expectedRows[i] {
+			t.Errorf("We got an unexpected result: %v instead of row %v", err, expectedRows[i])
+		}
+	}
+	if games[0].Favorite() != "NWE" || games[1].Favorite() != "GNB" || games[2].Favorite() != "" {
+		t.Errorf("We got an unexpected result: %v %v %v", games[0].Favorite(), games[1].Favorite(), games[2].Favorite())
+	}
+	// Without AM or PM, the Sunday kickoffs can't be read.
+	if games, errs = ParseOddsTable(body, "test", week1, false); len(games) != 2 || len(errs) != 5 {
+		t.Errorf("We got an unexpected result: %v games and %v errors", len(games), len(errs))
+	}
+}
+
+func TestParseKickoff(t *testing.T) {
+	texts := []string{"Thu 9/10 8:30 PM ET", "9/13 1:00 ET", "9/13 13:00", "1/3/2016 4:25 PM", "2015-09-10T20:30:00-04:00", "Sun 9/13 12:00 ET", "9/13 9:30 AM", "9/13 11:00AM ET", "1/3 1:00 ET"}
+	assumePM := []bool{false, true, false, false, false, true, false, false, true}
+	expectedResults := []time.Time{thursday, sunday, sunday, time.Date(2016, 1, 3, 21, 25, 0, 0, time.UTC), thursday, sunday.Add(-time.Hour),
+		time.Date(2015, 9, 13, 13, 30, 0, 0, time.UTC), sunday.Add(-2 * time.Hour), time.Date(2016, 1, 3, 18, 0, 0, 0, time.UTC)}
+	reference := time.Date(2015, 12, 20, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < len(texts); i++ {
+		result, err := ParseKickoff(texts[i], week1, assumePM[i])
+		if i == len(texts)-1 {
+			result, err = ParseKickoff(texts[i], reference, assumePM[i])
+		}
+		if err != nil || !result.Equal(expectedResults[i]) {
+			t.Errorf("We got an unexpected result: %v %v instead of %v", result, err, expectedResults[i])
+		}
+	}
+	for _, val := range []string{"TBD", "9/13 1:00 ET", "Sun 9/13 9:30 ET"} {
+		if _, err := ParseKickoff(val, week1, false); err == nil {
+			t.Errorf("We expected an error for %q", val)
+		}
+	}
+}
+
+func TestAddUpcomingGamesSkipsMissingTeams(t *testing.T) {
+	teamData := NewAllTeamData().AddUpcomingGames([]UpcomingGame{{HomeTeam: "NWE", VisitingTeam: "", Spread: -7}})
+	if len(teamData) != 0 {
+		t.Errorf("We got an unexpected result: %v", teamData)
+	}
+}
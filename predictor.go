@@ -0,0 +1,196 @@
+package nflwp
+
+import "strings"
+
+// A SpreadEstimate is a predicted spread and win probability from the home team's point of view.
+type SpreadEstimate struct {
+	Spread         float64
+	WinProbability float64
+}
+
+// A Predictor estimates the spread of a game from the season data gathered so far.
+// Both teams are expected to have played the games PredictorMinGames asks for; most of the estimates divide by GAMESPLAYED.
+type Predictor interface {
+	Name() string
+	Predict(TeamData AllTeamData, HomeTeam, VisitingTeam string) SpreadEstimate
+}
+
+// Returns how many games each team needs to have played before the Predictor can be used.
+// Predictors can set this with a MinGames method; otherwise it is two since GuessOP doesn't count a team's first game.
+func PredictorMinGames(Guess Predictor) float64 {
+	if val, ok := Guess.(interface{ MinGames() float64 }); ok {
+		return val.MinGames()
+	}
+	return 2
+}
+
+// Returns the Predictor's estimate with win probabilities and spreads from the sport's MarginStdDev.
+// Predictors can do this with a PredictForSport method; otherwise it is what Predict gives.
+func PredictorEstimate(Guess Predictor, TeamData AllTeamData, HomeTeam, VisitingTeam string, ThisSport Sport) SpreadEstimate {
+	if val, ok := Guess.(interface {
+		PredictForSport(TeamData AllTeamData, HomeTeam, VisitingTeam string, ThisSport Sport) SpreadEstimate
+	}); ok {
+		return val.PredictForSport(TeamData, HomeTeam, VisitingTeam, ThisSport)
+	}
+	return Guess.Predict(TeamData, HomeTeam, VisitingTeam)
+}
+
+// Turn a spread into a SpreadEstimate.
+func newSpreadEstimate(Spread float64) SpreadEstimate {
+	return newSpreadEstimateForSport(Spread, NFL)
+}
+
+// Like newSpreadEstimate, but with the sport's MarginStdDev.
+func newSpreadEstimateForSport(Spread float64, ThisSport Sport) SpreadEstimate {
+	return SpreadEstimate{Spread: Spread, WinProbability: WinProbability(0, Spread, ThisSport.MarginStdDev)}
+}
+
+// The difference in the average STRAIGHTWPADJUST of the teams.
+func straightDifference(TeamData AllTeamData, HomeTeam, VisitingTeam string) float64 {
+	return TeamData[HomeTeam][STRAIGHTWPADJUST]/TeamData[HomeTeam][GAMESPLAYED] - TeamData[VisitingTeam][STRAIGHTWPADJUST]/TeamData[VisitingTeam][GAMESPLAYED]
+}
+
+// Half the difference in the average WPADJUST of the teams.
+func wpDifference(TeamData AllTeamData, HomeTeam, VisitingTeam string) float64 {
+	return (-TeamData[VisitingTeam][WPADJUST]/TeamData[VisitingTeam][GAMESPLAYED] + TeamData[HomeTeam][WPADJUST]/TeamData[HomeTeam][GAMESPLAYED]) / 2
+}
+
+// Half the difference in the average OPPWPADJUST of the teams.
+// The first game a team plays never has an opponent adjustment, so it isn't counted.
+func opDifference(TeamData AllTeamData, HomeTeam, VisitingTeam string) float64 {
+	return (-TeamData[HomeTeam][OPPWPADJUST]/(TeamData[HomeTeam][GAMESPLAYED]-1) + TeamData[VisitingTeam][OPPWPADJUST]/(TeamData[VisitingTeam][GAMESPLAYED]-1)) / 2
+}
+
+// GuessSpreadPredictor finds a spread from the difference in the teams' STRAIGHTWPADJUST alone.
+type GuessSpreadPredictor struct{}
+
+func (GuessSpreadPredictor) Name() string {
+	return "GuessSpread"
+}
+
+func (p GuessSpreadPredictor) Predict(TeamData AllTeamData, HomeTeam, VisitingTeam string) SpreadEstimate {
+	return p.PredictForSport(TeamData, HomeTeam, VisitingTeam, NFL)
+}
+
+func (GuessSpreadPredictor) PredictForSport(TeamData AllTeamData, HomeTeam, VisitingTeam string, ThisSport Sport) SpreadEstimate {
+	return newSpreadEstimateForSport(NewSpread(0.5+straightDifference(TeamData, HomeTeam, VisitingTeam), 0.0, ThisSport.MarginStdDev), ThisSport)
+}
+
+// GuessWPPredictor adds the difference in the teams' WPADJUST to GuessSpread.
+type GuessWPPredictor struct{}
+
+func (GuessWPPredictor) Name() string {
+	return "GuessWP"
+}
+
+func (p GuessWPPredictor) Predict(TeamData AllTeamData, HomeTeam, VisitingTeam string) SpreadEstimate {
+	return p.PredictForSport(TeamData, HomeTeam, VisitingTeam, NFL)
+}
+
+func (GuessWPPredictor) PredictForSport(TeamData AllTeamData, HomeTeam, VisitingTeam string, ThisSport Sport) SpreadEstimate {
+	return newSpreadEstimateForSport(NewSpread(0.5+wpDifference(TeamData, HomeTeam, VisitingTeam)+straightDifference(TeamData, HomeTeam, VisitingTeam), 0.0, ThisSport.MarginStdDev), ThisSport)
+}
+
+// GuessOPPredictor adds the difference in the teams' OPPWPADJUST to GuessSpread.
+type GuessOPPredictor struct{}
+
+func (GuessOPPredictor) Name() string {
+	return "GuessOP"
+}
+
+func (p GuessOPPredictor) Predict(TeamData AllTeamData, HomeTeam, VisitingTeam string) SpreadEstimate {
+	return p.PredictForSport(TeamData, HomeTeam, VisitingTeam, NFL)
+}
+
+func (GuessOPPredictor) PredictForSport(TeamData AllTeamData, HomeTeam, VisitingTeam string, ThisSport Sport) SpreadEstimate {
+	return newSpreadEstimateForSport(NewSpread(0.5+opDifference(TeamData, HomeTeam, VisitingTeam)+straightDifference(TeamData, HomeTeam, VisitingTeam), 0.0, ThisSport.MarginStdDev), ThisSport)
+}
+
+// GuessBothPredictor adds the average of the WPADJUST and OPPWPADJUST differences to GuessSpread.
+type GuessBothPredictor struct{}
+
+func (GuessBothPredictor) Name() string {
+	return "GuessBoth"
+}
+
+func (p GuessBothPredictor) Predict(TeamData AllTeamData, HomeTeam, VisitingTeam string) SpreadEstimate {
+	return p.PredictForSport(TeamData, HomeTeam, VisitingTeam, NFL)
+}
+
+func (GuessBothPredictor) PredictForSport(TeamData AllTeamData, HomeTeam, VisitingTeam string, ThisSport Sport) SpreadEstimate {
+	Both := (wpDifference(TeamData, HomeTeam, VisitingTeam) + opDifference(TeamData, HomeTeam, VisitingTeam)) / 2.0
+	return newSpreadEstimateForSport(NewSpread(0.5+Both+straightDifference(TeamData, HomeTeam, VisitingTeam), 0.0, ThisSport.MarginStdDev), ThisSport)
+}
+
+// EstSpreadPredictor moves the win probability of the home team's SPREAD by the difference in the teams' WPADJUST.
+type EstSpreadPredictor struct{}
+
+func (EstSpreadPredictor) Name() string {
+	return "EstSpread"
+}
+
+func (p EstSpreadPredictor) Predict(TeamData AllTeamData, HomeTeam, VisitingTeam string) SpreadEstimate {
+	return p.PredictForSport(TeamData, HomeTeam, VisitingTeam, NFL)
+}
+
+func (EstSpreadPredictor) PredictForSport(TeamData AllTeamData, HomeTeam, VisitingTeam string, ThisSport Sport) SpreadEstimate {
+	NewProb := WinProbability(0, TeamData[HomeTeam][SPREAD], ThisSport.MarginStdDev) + wpDifference(TeamData, HomeTeam, VisitingTeam)
+	return newSpreadEstimateForSport(NewSpread(NewProb, TeamData[HomeTeam][SPREAD], ThisSport.MarginStdDev), ThisSport)
+}
+
+// EnsemblePredictor averages the spreads of other predictors.
+type EnsemblePredictor struct {
+	Predictors []Predictor
+}
+
+// Returns an EnsemblePredictor of the given predictors.
+// With no predictors, the five default predictors are used.
+func NewEnsemblePredictor(Predictors ...Predictor) EnsemblePredictor {
+	if len(Predictors) == 0 {
+		Predictors = DefaultPredictors()
+	}
+	return EnsemblePredictor{Predictors: Predictors}
+}
+
+func (EnsemblePredictor) Name() string {
+	return "Ensemble"
+}
+
+// The ensemble needs as many games as its most demanding predictor.
+func (e EnsemblePredictor) MinGames() float64 {
+	MinGames := 0.0
+	for _, val := range e.Predictors {
+		if m := PredictorMinGames(val); m > MinGames {
+			MinGames = m
+		}
+	}
+	return MinGames
+}
+
+func (e EnsemblePredictor) Predict(TeamData AllTeamData, HomeTeam, VisitingTeam string) SpreadEstimate {
+	return e.PredictForSport(TeamData, HomeTeam, VisitingTeam, NFL)
+}
+
+func (e EnsemblePredictor) PredictForSport(TeamData AllTeamData, HomeTeam, VisitingTeam string, ThisSport Sport) SpreadEstimate {
+	Spread := 0.0
+	for _, val := range e.Predictors {
+		Spread += PredictorEstimate(val, TeamData, HomeTeam, VisitingTeam, ThisSport).Spread
+	}
+	return newSpreadEstimateForSport(Spread/float64(len(e.Predictors)), ThisSport)
+}
+
+// The five predictors in the order CreateDataFromSpreadFiles writes them.
+func DefaultPredictors() []Predictor {
+	return []Predictor{GuessSpreadPredictor{}, GuessWPPredictor{}, GuessOPPredictor{}, GuessBothPredictor{}, EstSpreadPredictor{}}
+}
+
+// The predictor in the list with the given name, ignoring case, or the first one if the name is empty.
+// Returns false if there isn't one.
+func FindPredictor(Predictors []Predictor, Name string) (Predictor, bool) {
+	for _, val := range Predictors {
+		if Name == "" || strings.EqualFold(val.Name(), Name) {
+			return val, true
+		}
+	}
+	return nil, false
+}
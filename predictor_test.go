@@ -0,0 +1,80 @@
+package nflwp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPredictors(t *testing.T) {
+	TeamData := NewAllTeamData()
+	TeamData["NWE"] = NewTeamData()
+	TeamData["NWE"][GAMESPLAYED] = 4
+	TeamData["NWE"][WPADJUST] = 0.4
+	TeamData["NWE"][STRAIGHTWPADJUST] = 2.0
+	TeamData["NWE"][OPPWPADJUST] = 0.3
+	TeamData["NWE"][SPREAD] = -3
+	TeamData["BUF"] = NewTeamData()
+	TeamData["BUF"][GAMESPLAYED] = 4
+	TeamData["BUF"][WPADJUST] = -0.4
+	TeamData["BUF"][STRAIGHTWPADJUST] = 2.0
+	TeamData["BUF"][OPPWPADJUST] = 0.3
+	TeamData["BUF"][SPREAD] = 3
+	// The straight adjustments are equal, so GuessSpread is a pick'em.
+	// The home team has the better WPADJUST, so GuessWP and EstSpread favor them more than the line.
+	// The opponent adjustments are equal, so GuessOP matches GuessSpread and GuessBoth favors the home team by less than GuessWP.
+	predictors := DefaultPredictors()
+	results := make([]float64, len(predictors))
+	for i := 0; i < len(predictors); i++ {
+		results[i] = predictors[i].Predict(TeamData, "NWE", "BUF").Spread
+	}
+	if math.Abs(results[0]) > 0.05 {
+		t.Errorf("We got an unexpected result: %v instead of %v", results[0], 0.0)
+	}
+	if results[1] >= 0 {
+		t.Errorf("We got an unexpected result: %v, the home team should be favored", results[1])
+	}
+	if math.Abs(results[2]-results[0]) > 0.05 {
+		t.Errorf("We got an unexpected result: %v instead of %v", results[2], results[0])
+	}
+	if results[3] >= 0 || results[3] <= results[1] {
+		t.Errorf("We got an unexpected result: %v, the home team should be favored by less than %v", results[3], results[1])
+	}
+	if results[4] >= -3 {
+		t.Errorf("We got an unexpected result: %v, the home team should be favored by more than the line", results[4])
+	}
+	expected := (results[0] + results[1] + results[2] + results[3] + results[4]) / 5
+	result := NewEnsemblePredictor().Predict(TeamData, "NWE", "BUF")
+	if math.Abs(result.Spread-expected) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", result.Spread, expected)
+	}
+	if math.Abs(result.WinProbability-WinProbability(0, expected, STDDEV)) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", result.WinProbability, WinProbability(0, expected, STDDEV))
+	}
+	if guess, ok := FindPredictor(predictors, "estspread"); !ok || guess.Name() != predictors[4].Name() {
+		t.Errorf("We got an unexpected result: %v instead of %v", guess, predictors[4].Name())
+	}
+	if guess, ok := FindPredictor(predictors, ""); !ok || guess.Name() != predictors[0].Name() {
+		t.Errorf("We got an unexpected result: %v instead of %v", guess, predictors[0].Name())
+	}
+	if _, ok := FindPredictor(predictors, "Coin"); ok {
+		t.Errorf("We expected no predictor named Coin")
+	}
+}
+
+func TestPredictorEstimate(t *testing.T) {
+	TeamData := NewAllTeamData()
+	TeamData["NWE"] = []float64{0.4, 1.2, 4, 3, 0.3, -3, 0}
+	TeamData["BUF"] = []float64{-0.4, 0.8, 4, 1, 0.3, 3, 0}
+	// With the NFL it is the same as Predict, and college margins are wider so the spreads are bigger.
+	for _, guess := range append(DefaultPredictors(), NewEnsemblePredictor()) {
+		nfl, ncaaf := PredictorEstimate(guess, TeamData, "NWE", "BUF", NFL), PredictorEstimate(guess, TeamData, "NWE", "BUF", NCAAF)
+		if nfl != guess.Predict(TeamData, "NWE", "BUF") || ncaaf.Spread >= nfl.Spread || math.Abs(ncaaf.WinProbability-WinProbability(0, ncaaf.Spread, NCAAF.MarginStdDev)) > 1e-9 {
+			t.Errorf("We got an unexpected result for %v: %+v and %+v", guess.Name(), nfl, ncaaf)
+		}
+	}
+	// A predictor without PredictForSport is just Predict.
+	elo := EloPredictor{Elo: NewElo()}
+	if result := PredictorEstimate(elo, TeamData, "NWE", "BUF", NCAAF); result != elo.Predict(TeamData, "NWE", "BUF") {
+		t.Errorf("We got an unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,421 @@
+package nflwp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A LineProvider gets the current lines for the week's games from a sportsbook or odds site.
+// Games are returned with Source set to the provider's Name.
+type LineProvider interface {
+	Name() string
+	Lines() ([]UpcomingGame, error)
+}
+
+// A Fetcher returns the body of the page at a URL. Providers use HTTPFetch unless they are given another,
+// which is how the tests read the pages from testdata instead.
+type Fetcher func(URL string) ([]byte, error)
+
+// Get the page with net/http.
+func HTTPFetch(URL string) ([]byte, error) {
+	response, err := http.Get(URL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got %v from %v", response.Status, URL)
+	}
+	return ioutil.ReadAll(response.Body)
+}
+
+func fetch(Fetch Fetcher, URL, Default string) ([]byte, error) {
+	if Fetch == nil {
+		Fetch = HTTPFetch
+	}
+	if URL == "" {
+		URL = Default
+	}
+	return Fetch(URL)
+}
+
+// Turn a team from an odds page, either a nickname like "Patriots" or an abbreviation like "NWE", into its abbreviation.
+// Returns "" if we don't know the team.
+func GetTeamAbbrFromOddsName(Name string) string {
+	Name = strings.ToUpper(strings.TrimSpace(Name))
+	if GetTeamFloatFromAbbr(Name) != 0 {
+		return Name
+	}
+	Fields := strings.Fields(Name)
+	if len(Fields) == 0 {
+		return ""
+	}
+	// Full names like "New England Patriots" end in the nickname.
+	return GetPFRTeamAbbr(Fields[len(Fields)-1])
+}
+
+// Strip a leading "at " or "@" from a team and say whether it was there, which is how odds pages mark the home team.
+func stripHome(Team string) (string, bool) {
+	Team = strings.TrimSpace(Team)
+	if strings.HasPrefix(strings.ToLower(Team), "at ") {
+		return strings.TrimSpace(Team[3:]), true
+	}
+	if strings.HasPrefix(Team, "@") {
+		return strings.TrimSpace(Team[1:]), true
+	}
+	return Team, false
+}
+
+var (
+	tableRowRegexp  = regexp.MustCompile(`(?is)<tr[^>]*>(.*?)</tr>`)
+	tableCellRegexp = regexp.MustCompile(`(?is)<t[dh][^>]*>(.*?)</t[dh]>`)
+	htmlTagRegexp   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Given the HTML of a table, return the text of the cells of each row. Tags inside the cells are dropped.
+func tableRows(body []byte) [][]string {
+	var Rows [][]string
+	for _, Row := range tableRowRegexp.FindAllSubmatch(body, -1) {
+		var Cells []string
+		for _, Cell := range tableCellRegexp.FindAllSubmatch(Row[1], -1) {
+			Text := htmlTagRegexp.ReplaceAllString(string(Cell[1]), "")
+			Cells = append(Cells, strings.Join(strings.Fields(html.UnescapeString(Text)), " "))
+		}
+		if len(Cells) > 0 {
+			Rows = append(Rows, Cells)
+		}
+	}
+	return Rows
+}
+
+// Parse a line like "-7", "+3.5", "PK" or "Pick".
+func parseLine(Line string) (float64, error) {
+	Line = strings.TrimSpace(Line)
+	if strings.EqualFold(Line, "PK") || strings.EqualFold(Line, "Pick") || strings.EqualFold(Line, "EVEN") {
+		return 0, nil
+	}
+	return strconv.ParseFloat(strings.TrimPrefix(Line, "+"), 64)
+}
+
+// Parse a moneyline like "-150", "+130" or "EVEN", which is +100.
+func parseMoneyline(Line string) (float64, error) {
+	if strings.EqualFold(strings.TrimSpace(Line), "EVEN") {
+		return 100, nil
+	}
+	return strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(Line), "+"), 64)
+}
+
+// Given the favorite and underdog with the home team marked and the favorite's spread, make the game.
+// It is an error if we don't know a team or if neither or both are marked.
+func gameFromFavorite(Favorite, Underdog string, Spread float64) (UpcomingGame, error) {
+	Favorite, FavoriteHome := stripHome(Favorite)
+	Underdog, UnderdogHome := stripHome(Underdog)
+	Fav, Dog := GetTeamAbbrFromOddsName(Favorite), GetTeamAbbrFromOddsName(Underdog)
+	switch {
+	case Fav == "":
+		return UpcomingGame{}, fmt.Errorf("we don't know the team %q", Favorite)
+	case Dog == "":
+		return UpcomingGame{}, fmt.Errorf("we don't know the team %q", Underdog)
+	case FavoriteHome == UnderdogHome:
+		return UpcomingGame{}, fmt.Errorf("can't tell which of %v and %v is at home", Favorite, Underdog)
+	}
+	Spread = -math.Abs(Spread)
+	if FavoriteHome {
+		return UpcomingGame{HomeTeam: Fav, VisitingTeam: Dog, Spread: Spread}, nil
+	}
+	return UpcomingGame{HomeTeam: Dog, VisitingTeam: Fav, Spread: -Spread}, nil
+}
+
+// FantasyDataProvider reads fantasydata.com's StatsGrid table.
+// The columns are the favorite, its spread, the underdog, the over/under and the two moneylines,
+// and the home team is marked with "at ". Reference is when the week is, for kickoffs without a year; it is now if zero.
+type FantasyDataProvider struct {
+	URL       string
+	Fetch     Fetcher
+	Reference time.Time
+}
+
+func (FantasyDataProvider) Name() string {
+	return "fantasydata"
+}
+
+func (p FantasyDataProvider) Lines() ([]UpcomingGame, error) {
+	body, err := fetch(p.Fetch, p.URL, "https://fantasydata.com/nfl-stats/nfl-point-spreads-and-odds.aspx")
+	if err != nil {
+		return nil, err
+	}
+	Games, Errors := ParseFantasyDataLines(body, reference(p.Reference))
+	printErrors(Errors)
+	if len(Games) == 0 {
+		return nil, fmt.Errorf("there are no games on the page")
+	}
+	return Games, nil
+}
+
+// Parse the games out of a fantasydata.com odds page with ParseOddsTable.
+// If there is no StatsGrid table on the page, the only error is about that.
+func ParseFantasyDataLines(body []byte, Reference time.Time) ([]UpcomingGame, []error) {
+	Index := bytes.Index(body, []byte("StatsGrid"))
+	if Index < 0 {
+		return nil, []error{fmt.Errorf("there is no StatsGrid table on the page")}
+	}
+	body = body[Index:]
+	if Index = bytes.Index(body, []byte("</table>")); Index >= 0 {
+		body = body[:Index]
+	}
+	return ParseOddsTable(body, "fantasydata", Reference, false)
+}
+
+// Put the favorite's and underdog's moneylines on the right sides of the game.
+// The favorite is the team named in the favorite column, which matters for a pick'em.
+func moneylines(FavoriteTeam, Favorite, Underdog string) (float64, float64) {
+	Fav, _ := parseMoneyline(Favorite)
+	Dog, _ := parseMoneyline(Underdog)
+	if _, Home := stripHome(FavoriteTeam); Home {
+		return Fav, Dog
+	}
+	return Dog, Fav
+}
+
+// The time to place kickoffs without a year near.
+func reference(Reference time.Time) time.Time {
+	if Reference.IsZero() {
+		return time.Now()
+	}
+	return Reference
+}
+
+func printErrors(Errors []error) {
+	for _, err := range Errors {
+		fmt.Println("Error: ", err)
+	}
+}
+
+// FootballLocksProvider reads the NFL lines table from footballlocks.com.
+// The columns are the kickoff, the favorite, its spread, the underdog and the total, with the home team marked with "At ".
+// The kickoffs leave off AM and PM, so they are read as PM. Reference is the same as FantasyDataProvider's.
+type FootballLocksProvider struct {
+	URL       string
+	Fetch     Fetcher
+	Reference time.Time
+}
+
+func (FootballLocksProvider) Name() string {
+	return "footballlocks"
+}
+
+func (p FootballLocksProvider) Lines() ([]UpcomingGame, error) {
+	body, err := fetch(p.Fetch, p.URL, "http://www.footballlocks.com/nfl_lines.shtml")
+	if err != nil {
+		return nil, err
+	}
+	Games, Errors := ParseOddsTable(body, "footballlocks", reference(p.Reference), true)
+	printErrors(Errors)
+	if len(Games) == 0 {
+		return nil, fmt.Errorf("there are no games on the page")
+	}
+	return Games, nil
+}
+
+// JSONFeedProvider reads lines from a JSON feed in the format ParseJSONLines documents.
+// Source names the feed, and is "json" if it is empty.
+type JSONFeedProvider struct {
+	Source string
+	URL    string
+	Fetch  Fetcher
+}
+
+func (p JSONFeedProvider) Name() string {
+	if p.Source == "" {
+		return "json"
+	}
+	return p.Source
+}
+
+func (p JSONFeedProvider) Lines() ([]UpcomingGame, error) {
+	if p.URL == "" {
+		return nil, fmt.Errorf("the JSON feed %v has no URL", p.Name())
+	}
+	body, err := fetch(p.Fetch, p.URL, "")
+	if err != nil {
+		return nil, err
+	}
+	Games, Errors := ParseJSONLines(body)
+	printErrors(Errors)
+	if len(Games) == 0 {
+		return nil, fmt.Errorf("there are no games in the feed")
+	}
+	for i := range Games {
+		Games[i].Source = p.Name()
+	}
+	return Games, nil
+}
+
+// A jsonGame is a game in a JSON odds feed.
+type jsonGame struct {
+	Home              string   `json:"home"`
+	Away              string   `json:"away"`
+	Spread            *float64 `json:"spread"`
+	Total             float64  `json:"total"`
+	HomeMoneyline     float64  `json:"home_moneyline"`
+	VisitingMoneyline float64  `json:"away_moneyline"`
+	Kickoff           string   `json:"kickoff"`
+}
+
+// Parse a JSON odds feed of the form
+//
+//	{"games": [{"home": "NWE", "away": "PIT", "spread": -7, "total": 51, "home_moneyline": -300, "away_moneyline": 250,
+//	  "kickoff": "2015-09-10T20:30:00-04:00"}]}
+//
+// Teams can be abbreviations or names, spread is from the home team's point of view and kickoff is RFC 3339.
+// Games without a spread are skipped. Like ParseOddsTable, games with teams we don't know or kickoffs we can't read
+// are returned as errors instead of games, and if the feed isn't JSON that is the only error.
+func ParseJSONLines(body []byte) ([]UpcomingGame, []error) {
+	var Feed struct {
+		Games []jsonGame `json:"games"`
+	}
+	if err := json.Unmarshal(body, &Feed); err != nil {
+		return nil, []error{err}
+	}
+	var Games []UpcomingGame
+	var Errors []error
+	for _, val := range Feed.Games {
+		Home, Away := GetTeamAbbrFromOddsName(val.Home), GetTeamAbbrFromOddsName(val.Away)
+		if Home == "" || Away == "" {
+			Errors = append(Errors, fmt.Errorf("we don't know the teams in %v at %v", val.Away, val.Home))
+			continue
+		}
+		if val.Spread == nil {
+			continue
+		}
+		Game := UpcomingGame{HomeTeam: Home, VisitingTeam: Away, Spread: *val.Spread, Total: val.Total,
+			HomeMoneyline: val.HomeMoneyline, VisitingMoneyline: val.VisitingMoneyline, Source: "json"}
+		if val.Kickoff != "" {
+			Kickoff, err := time.Parse(time.RFC3339, val.Kickoff)
+			if err != nil {
+				Errors = append(Errors, fmt.Errorf("bad kickoff for %v at %v: %v", val.Away, val.Home, err))
+				continue
+			}
+			Game.Kickoff = Kickoff
+		}
+		Games = append(Games, Game)
+	}
+	return Games, Errors
+}
+
+// ConsensusProvider asks each of its providers for lines and takes the median of each game's spread, total and moneylines.
+// Providers that fail are printed and left out; it is only an error if all of them fail.
+type ConsensusProvider struct {
+	Providers []LineProvider
+}
+
+func (ConsensusProvider) Name() string {
+	return "consensus"
+}
+
+func (p ConsensusProvider) Lines() ([]UpcomingGame, error) {
+	var AllGames [][]UpcomingGame
+	var LastErr error
+	for _, Provider := range p.Providers {
+		Games, err := Provider.Lines()
+		if err != nil {
+			fmt.Printf("Error getting lines from %v: %v\n", Provider.Name(), err)
+			LastErr = err
+			continue
+		}
+		AllGames = append(AllGames, Games)
+	}
+	if len(AllGames) == 0 && LastErr != nil {
+		return nil, LastErr
+	}
+	return ConsensusLines(AllGames...), nil
+}
+
+// Given the games from several providers, combine them into one line per game by taking medians.
+// Providers can disagree on which team is at home, as at a neutral site, so games are matched on the two teams and
+// listed the way the first provider to have the game lists it, with the other providers' lines turned around to match.
+// Zero totals and moneylines mean the provider didn't have one and don't count. Moneylines jump from -100 to +100,
+// so their median is taken of the probabilities they imply and turned back into a moneyline.
+// The kickoff is the earliest any provider has. Games are sorted by home team.
+func ConsensusLines(Providers ...[]UpcomingGame) []UpcomingGame {
+	type key struct{ Team, OtherTeam string }
+	Teams := make(map[key]UpcomingGame)
+	Spreads := make(map[key][]float64)
+	Totals := make(map[key][]float64)
+	HomeMLs := make(map[key][]float64)
+	VisitorMLs := make(map[key][]float64)
+	Kickoffs := make(map[key]time.Time)
+	for _, Games := range Providers {
+		for _, val := range Games {
+			k := key{val.HomeTeam, val.VisitingTeam}
+			if k.OtherTeam < k.Team {
+				k = key{val.VisitingTeam, val.HomeTeam}
+			}
+			First, ok := Teams[k]
+			if !ok {
+				First = UpcomingGame{HomeTeam: val.HomeTeam, VisitingTeam: val.VisitingTeam}
+				Teams[k] = First
+			}
+			Spread, HomeML, VisitorML := val.Spread, val.HomeMoneyline, val.VisitingMoneyline
+			if val.HomeTeam != First.HomeTeam {
+				Spread, HomeML, VisitorML = -Spread, VisitorML, HomeML
+			}
+			Spreads[k] = append(Spreads[k], Spread)
+			if val.Total != 0 {
+				Totals[k] = append(Totals[k], val.Total)
+			}
+			if !val.Kickoff.IsZero() && (Kickoffs[k].IsZero() || val.Kickoff.Before(Kickoffs[k])) {
+				Kickoffs[k] = val.Kickoff
+			}
+			if HomeML != 0 && VisitorML != 0 {
+				HomeMLs[k] = append(HomeMLs[k], MoneylineProbability(HomeML))
+				VisitorMLs[k] = append(VisitorMLs[k], MoneylineProbability(VisitorML))
+			}
+		}
+	}
+	Games := make([]UpcomingGame, 0, len(Spreads))
+	for k, val := range Spreads {
+		Games = append(Games, UpcomingGame{HomeTeam: Teams[k].HomeTeam, VisitingTeam: Teams[k].VisitingTeam, Spread: median(val), Total: median(Totals[k]),
+			HomeMoneyline: medianMoneyline(HomeMLs[k]), VisitingMoneyline: medianMoneyline(VisitorMLs[k]), Kickoff: Kickoffs[k], Source: "consensus"})
+	}
+	sort.Slice(Games, func(i, j int) bool { return Games[i].HomeTeam < Games[j].HomeTeam })
+	return Games
+}
+
+// The median of the values, or 0 if there aren't any.
+func median(Values []float64) float64 {
+	if len(Values) == 0 {
+		return 0
+	}
+	Sorted := append([]float64(nil), Values...)
+	sort.Float64s(Sorted)
+	Middle := len(Sorted) / 2
+	if len(Sorted)%2 == 1 {
+		return Sorted[Middle]
+	}
+	return (Sorted[Middle-1] + Sorted[Middle]) / 2
+}
+
+// The moneyline for the median of the probabilities, or 0 if there aren't any.
+func medianMoneyline(Probabilities []float64) float64 {
+	if len(Probabilities) == 0 {
+		return 0
+	}
+	return ProbabilityMoneyline(median(Probabilities))
+}
+
+// The game's line as a quote for a LineHistory, recorded at the given time.
+func (g UpcomingGame) Quote(RecordedAt time.Time) LineQuote {
+	return LineQuote{Source: g.Source, RecordedAt: RecordedAt, Spread: g.Spread, Total: g.Total,
+		HomeMoneyline: g.HomeMoneyline, VisitingMoneyline: g.VisitingMoneyline}
+}
@@ -0,0 +1,145 @@
+package nflwp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+// A Fetcher that reads the page from testdata.
+func fixture(Name string) Fetcher {
+	return func(URL string) ([]byte, error) {
+		return ioutil.ReadFile("testdata/" + Name)
+	}
+}
+
+func failingFetch(URL string) ([]byte, error) {
+	return nil, fmt.Errorf("no network in the tests")
+}
+
+func checkGames(t *testing.T, games, expected []UpcomingGame) {
+	if len(games) != len(expected) {
+		t.Fatalf("We got an unexpected result: %v instead of %v", games, expected)
+	}
+	for i := range expected {
+		game, kickoff := games[i], games[i].Kickoff
+		game.Kickoff = expected[i].Kickoff
+		if game != expected[i] || !kickoff.Equal(expected[i].Kickoff) {
+			t.Errorf("We got an unexpected result: %v instead of %v", games[i], expected[i])
+		}
+	}
+}
+
+func TestFantasyDataProvider(t *testing.T) {
+	games, err := FantasyDataProvider{Fetch: fixture("fantasydata.html")}.Lines()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGames(t, games, []UpcomingGame{
+		{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -7, Total: 51, HomeMoneyline: -300, VisitingMoneyline: 250, Source: "fantasydata"},
+		{HomeTeam: "CHI", VisitingTeam: "GNB", Spread: 6.5, Total: 48, HomeMoneyline: 230, VisitingMoneyline: -280, Source: "fantasydata"},
+		{HomeTeam: "NYJ", VisitingTeam: "CLE", Spread: -3, Total: 40.5, HomeMoneyline: -160, VisitingMoneyline: 140, Source: "fantasydata"},
+	})
+	if _, errs := ParseFantasyDataLines([]byte("<html></html>"), time.Now()); len(errs) != 1 {
+		t.Errorf("We expected an error for a page without the table")
+	}
+	empty := func(URL string) ([]byte, error) { return []byte("<html></html>"), nil }
+	if _, err = (FantasyDataProvider{Fetch: empty}).Lines(); err == nil {
+		t.Errorf("We expected an error for a page without any games")
+	}
+	if home, visiting := moneylines("at New England", "EVEN", "-120"); home != 100 || visiting != -120 {
+		t.Errorf("We got an unexpected result: %v and %v instead of %v and %v", home, visiting, 100, -120)
+	}
+}
+
+var (
+	week1    = time.Date(2015, 9, 8, 0, 0, 0, 0, time.UTC)
+	thursday = time.Date(2015, 9, 11, 0, 30, 0, 0, time.UTC)
+	sunday   = time.Date(2015, 9, 13, 17, 0, 0, 0, time.UTC)
+)
+
+func TestFootballLocksProvider(t *testing.T) {
+	games, err := FootballLocksProvider{Fetch: fixture("footballlocks.html"), Reference: week1}.Lines()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGames(t, games, []UpcomingGame{
+		{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -7.5, Total: 51.5, Kickoff: thursday, Source: "footballlocks"},
+		{HomeTeam: "CHI", VisitingTeam: "GNB", Spread: 6, Total: 48, Kickoff: sunday, Source: "footballlocks"},
+		{HomeTeam: "NYJ", VisitingTeam: "CLE", Spread: 0, Total: 41, Kickoff: sunday, Source: "footballlocks"},
+	})
+}
+
+func TestJSONFeedProvider(t *testing.T) {
+	games, err := JSONFeedProvider{Source: "feed", URL: "http://example.com/odds.json", Fetch: fixture("odds.json")}.Lines()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGames(t, games, []UpcomingGame{
+		{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -7, Total: 50.5, HomeMoneyline: -320, VisitingMoneyline: 260, Kickoff: thursday, Source: "feed"},
+		{HomeTeam: "CHI", VisitingTeam: "GNB", Spread: 7, Total: 48.5, Source: "feed"},
+	})
+	if _, err = (JSONFeedProvider{}).Lines(); err == nil {
+		t.Errorf("We expected an error for a feed without a URL")
+	}
+	// The unknown team and the kickoff that isn't RFC 3339 are errors rather than games.
+	games, errs := ParseJSONLines([]byte(`{"games": [{"home": "NWE", "away": "PIT", "spread": -7, "kickoff": "Thu 8:30 PM"},
+		{"home": "Mystery Team", "away": "CLE", "spread": 3}, {"home": "CHI", "away": "GNB", "spread": 7}]}`))
+	if len(games) != 1 || games[0].HomeTeam != "CHI" || len(errs) != 2 {
+		t.Errorf("We got an unexpected result: %v %v", games, errs)
+	}
+	if _, errs = ParseJSONLines([]byte("not json")); len(errs) != 1 {
+		t.Errorf("We got an unexpected result: %v", errs)
+	}
+}
+
+func TestConsensusLinesNeutralSite(t *testing.T) {
+	// The second provider has PIT at home, so its line is turned around to match the first.
+	games := ConsensusLines(
+		[]UpcomingGame{{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -7, HomeMoneyline: -300, VisitingMoneyline: 250}},
+		[]UpcomingGame{{HomeTeam: "PIT", VisitingTeam: "NWE", Spread: 6, HomeMoneyline: 240, VisitingMoneyline: -280}},
+	)
+	checkGames(t, games, []UpcomingGame{
+		{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -6.5, HomeMoneyline: -290, VisitingMoneyline: 245, Source: "consensus"},
+	})
+}
+
+func TestConsensusLinesMoneylines(t *testing.T) {
+	// The providers disagree on the favorite, so the moneylines straddle even money.
+	games := ConsensusLines(
+		[]UpcomingGame{{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -1, HomeMoneyline: -105, VisitingMoneyline: -115}},
+		[]UpcomingGame{{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: 1, HomeMoneyline: 105, VisitingMoneyline: -125}},
+		[]UpcomingGame{{HomeTeam: "CHI", VisitingTeam: "GNB", Spread: -1, HomeMoneyline: -120, VisitingMoneyline: 100}},
+		[]UpcomingGame{{HomeTeam: "CHI", VisitingTeam: "GNB", Spread: 1, HomeMoneyline: 110, VisitingMoneyline: -130}},
+	)
+	checkGames(t, games, []UpcomingGame{
+		{HomeTeam: "CHI", VisitingTeam: "GNB", Spread: 0, HomeMoneyline: -104, VisitingMoneyline: -114, Source: "consensus"},
+		{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: 0, HomeMoneyline: 100, VisitingMoneyline: -120, Source: "consensus"},
+	})
+}
+
+func TestConsensusProvider(t *testing.T) {
+	provider := ConsensusProvider{Providers: []LineProvider{
+		FantasyDataProvider{Fetch: fixture("fantasydata.html")},
+		FootballLocksProvider{Fetch: fixture("footballlocks.html"), Reference: week1},
+		JSONFeedProvider{URL: "http://example.com/odds.json", Fetch: fixture("odds.json")},
+		FootballLocksProvider{Fetch: failingFetch},
+	}}
+	games, err := provider.Lines()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGames(t, games, []UpcomingGame{
+		{HomeTeam: "CHI", VisitingTeam: "GNB", Spread: 6.5, Total: 48, HomeMoneyline: 230, VisitingMoneyline: -280, Kickoff: sunday, Source: "consensus"},
+		{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -7, Total: 51, HomeMoneyline: -310, VisitingMoneyline: 255, Kickoff: thursday, Source: "consensus"},
+		{HomeTeam: "NYJ", VisitingTeam: "CLE", Spread: -1.5, Total: 40.75, HomeMoneyline: -160, VisitingMoneyline: 140, Kickoff: sunday, Source: "consensus"},
+	})
+	if _, err = (ConsensusProvider{Providers: []LineProvider{FantasyDataProvider{Fetch: failingFetch}}}).Lines(); err == nil {
+		t.Errorf("We expected an error when every provider fails")
+	}
+	teamData := GetCurrentSpreadsFromProvider(NewAllTeamData(), provider)
+	if teamData["GNB"][SPREAD] != -6.5 || GetTeamAbbrFromFloat(teamData["GNB"][PLAYINGTHISWEEK]) != "CHI" {
+		t.Errorf("We got an unexpected result: %v", teamData["GNB"])
+	}
+}
@@ -0,0 +1,241 @@
+package nflwp
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// An UpcomingGame is a game that hasn't been played yet along with its line.
+// Spread is from the home team's point of view, so a negative spread means the home team is favored.
+// Total and the moneylines are 0 and Kickoff is zero if we don't have them, and Source is the LineProvider the line came from.
+type UpcomingGame struct {
+	HomeTeam          string
+	VisitingTeam      string
+	Spread            float64
+	Total             float64
+	HomeMoneyline     float64
+	VisitingMoneyline float64
+	Kickoff           time.Time
+	Source            string
+}
+
+// Fill in SPREAD and PLAYINGTHISWEEK for the teams playing in the given games.
+// Games missing a team are skipped. The teams are NFL teams, see AddUpcomingGamesForSport.
+func (a AllTeamData) AddUpcomingGames(Games []UpcomingGame) AllTeamData {
+	return a.AddUpcomingGamesForSport(Games, NFL)
+}
+
+// Like AddUpcomingGames, but the opponents are coded with the sport's TeamRegistry.
+func (a AllTeamData) AddUpcomingGamesForSport(Games []UpcomingGame, ThisSport Sport) AllTeamData {
+	for _, val := range Games {
+		if val.HomeTeam == "" || val.VisitingTeam == "" {
+			fmt.Printf("Error: skipping the game %v at %v since it is missing a team\n", val.VisitingTeam, val.HomeTeam)
+			continue
+		}
+		if _, ok := a[val.HomeTeam]; !ok {
+			a[val.HomeTeam] = NewTeamData()
+		}
+		if _, ok := a[val.VisitingTeam]; !ok {
+			a[val.VisitingTeam] = NewTeamData()
+		}
+		a[val.HomeTeam][SPREAD] = val.Spread
+		a[val.VisitingTeam][SPREAD] = -val.Spread
+		a[val.HomeTeam][PLAYINGTHISWEEK] = ThisSport.Teams.Float(val.VisitingTeam)
+		a[val.VisitingTeam][PLAYINGTHISWEEK] = ThisSport.Teams.Float(val.HomeTeam)
+	}
+	return a
+}
+
+// Copies of the game's two teams with SPREAD and PLAYINGTHISWEEK set from its line, since the predictors read the line from SPREAD.
+// A team we don't have data for starts from NewTeamData. The teams are NFL teams, see ForGameForSport.
+func (a AllTeamData) ForGame(Game UpcomingGame) AllTeamData {
+	return a.ForGameForSport(Game, NFL)
+}
+
+// Like ForGame, but the opponents are coded with the sport's TeamRegistry.
+func (a AllTeamData) ForGameForSport(Game UpcomingGame, ThisSport Sport) AllTeamData {
+	TeamData := NewAllTeamData()
+	for _, Team := range []string{Game.HomeTeam, Game.VisitingTeam} {
+		if val, ok := a[Team]; ok {
+			TeamData[Team] = append([]float64(nil), val...)
+		}
+	}
+	return TeamData.AddUpcomingGamesForSport([]UpcomingGame{Game}, ThisSport)
+}
+
+// A PredictionRow compares the market's line for a game to a Predictor's.
+// Everything is from the home team's point of view.
+// Edge is how many points better the home team is than the market thinks, so a positive edge means take the home team.
+type PredictionRow struct {
+	HomeTeam       string
+	VisitingTeam   string
+	MarketSpread   float64
+	AdjustedSpread float64
+	MarketWP       float64
+	AdjustedWP     float64
+	Edge           float64
+}
+
+// The side of the spread the row likes.
+func (r PredictionRow) Pick() string {
+	if r.Edge < 0 {
+		return r.VisitingTeam
+	}
+	return r.HomeTeam
+}
+
+// A PredictionReport is a list of PredictionRows, biggest edge first.
+type PredictionReport []PredictionRow
+
+// Given the season data so far and saved games, predict them against the lines they were saved with, the same way Predict does.
+func PredictGameResults(TeamData AllTeamData, Results []GameResult, Guess Predictor) PredictionReport {
+	return PredictGameResultsForSport(TeamData, Results, Guess, NFL)
+}
+
+// Like PredictGameResults, but the market's win probability comes from the sport's MarginStdDev, see PredictForSport.
+func PredictGameResultsForSport(TeamData AllTeamData, Results []GameResult, Guess Predictor, ThisSport Sport) PredictionReport {
+	Games := make([]UpcomingGame, len(Results))
+	for i, val := range Results {
+		Games[i] = UpcomingGame{HomeTeam: val.HomeTeam, VisitingTeam: val.VisitingTeam, Spread: val.Spread}
+	}
+	return PredictForSport(TeamData, Games, Guess, ThisSport)
+}
+
+// Given the season data so far and the lines for the upcoming games, predict each game with the given Predictor.
+// Games where either team hasn't played the games the Predictor needs are skipped, see PredictorMinGames,
+// and so are games without a line, whose Spread is NOSPREAD.
+func Predict(TeamData AllTeamData, Games []UpcomingGame, Guess Predictor) PredictionReport {
+	return PredictForSport(TeamData, Games, Guess, NFL)
+}
+
+// Like Predict, but the opponents are coded with the sport's TeamRegistry, and MarketWP and AdjustedWP are
+// the spreads' win probabilities with the sport's MarginStdDev.
+func PredictForSport(TeamData AllTeamData, Games []UpcomingGame, Guess Predictor, ThisSport Sport) PredictionReport {
+	var Report PredictionReport
+	MinGames := PredictorMinGames(Guess)
+	for _, val := range Games {
+		Home, ok := TeamData[val.HomeTeam]
+		if !ok {
+			Home = NewTeamData()
+		}
+		Visitor, ok := TeamData[val.VisitingTeam]
+		if !ok {
+			Visitor = NewTeamData()
+		}
+		if Home[GAMESPLAYED] < MinGames || Visitor[GAMESPLAYED] < MinGames || val.Spread == NOSPREAD {
+			continue
+		}
+		Estimate := Guess.Predict(TeamData.ForGameForSport(val, ThisSport), val.HomeTeam, val.VisitingTeam)
+		Report = append(Report, PredictionRow{
+			HomeTeam:       val.HomeTeam,
+			VisitingTeam:   val.VisitingTeam,
+			MarketSpread:   val.Spread,
+			AdjustedSpread: Estimate.Spread,
+			MarketWP:       WinProbability(0, val.Spread, ThisSport.MarginStdDev),
+			AdjustedWP:     WinProbability(0, Estimate.Spread, ThisSport.MarginStdDev),
+			Edge:           val.Spread - Estimate.Spread,
+		})
+	}
+	sort.SliceStable(Report, func(i, j int) bool { return math.Abs(Report[i].Edge) > math.Abs(Report[j].Edge) })
+	return Report
+}
+
+var predictionHeader = []string{"Home", "Visitor", "Market Spread", "Adjusted Spread", "Market WP", "Adjusted WP", "Edge", "Pick"}
+
+// The row as strings in the same order as predictionHeader.
+func (r PredictionRow) strings() []string {
+	return []string{
+		r.HomeTeam,
+		r.VisitingTeam,
+		strconv.FormatFloat(r.MarketSpread, 'f', 1, 64),
+		strconv.FormatFloat(r.AdjustedSpread, 'f', 1, 64),
+		strconv.FormatFloat(r.MarketWP, 'f', 3, 64),
+		strconv.FormatFloat(r.AdjustedWP, 'f', 3, 64),
+		strconv.FormatFloat(r.Edge, 'f', 1, 64),
+		r.Pick(),
+	}
+}
+
+// Write the report as an aligned text table.
+func (p PredictionReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if err := writeTabbed(tw, predictionHeader); err != nil {
+		return err
+	}
+	for _, val := range p {
+		if err := writeTabbed(tw, val.strings()); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func writeTabbed(w io.Writer, Fields []string) error {
+	_, err := fmt.Fprintln(w, strings.Join(Fields, "\t"))
+	return err
+}
+
+// Write the report as CSV with a header row.
+func (p PredictionReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(predictionHeader)
+	for _, val := range p {
+		cw.Write(val.strings())
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Write the report as a JSON array.
+func (p PredictionReport) WriteJSON(w io.Writer) error {
+	type jsonRow struct {
+		HomeTeam       string  `json:"home"`
+		VisitingTeam   string  `json:"visitor"`
+		MarketSpread   float64 `json:"market_spread"`
+		AdjustedSpread float64 `json:"adjusted_spread"`
+		MarketWP       float64 `json:"market_wp"`
+		AdjustedWP     float64 `json:"adjusted_wp"`
+		Edge           float64 `json:"edge"`
+		Pick           string  `json:"pick"`
+	}
+	Rows := make([]jsonRow, len(p))
+	for i, val := range p {
+		Rows[i] = jsonRow{val.HomeTeam, val.VisitingTeam, val.MarketSpread, val.AdjustedSpread, val.MarketWP, val.AdjustedWP, val.Edge, val.Pick()}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Rows)
+}
+
+// Write the report as a Markdown table.
+func (p PredictionReport) WriteMarkdown(w io.Writer) error {
+	Separator := make([]string, len(predictionHeader))
+	for i := range Separator {
+		Separator[i] = "---"
+	}
+	if err := writeMarkdownRow(w, predictionHeader); err != nil {
+		return err
+	}
+	if err := writeMarkdownRow(w, Separator); err != nil {
+		return err
+	}
+	for _, val := range p {
+		if err := writeMarkdownRow(w, val.strings()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMarkdownRow(w io.Writer, Fields []string) error {
+	_, err := fmt.Fprintf(w, "| %v |\n", strings.Join(Fields, " | "))
+	return err
+}
@@ -0,0 +1,99 @@
+package nflwp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Four weeks of NWE beating BUF and DEN beating KAN, so NWE's average WPADJUST is 0.1 and DEN's is 0.0125.
+func reportTeamData() AllTeamData {
+	builder := NewSeasonBuilder(nil)
+	for week := 1; week <= 4; week++ {
+		builder.AddGameResult(testResult(week, "NWE", "BUF", 0.1))
+		builder.AddGameResult(testResult(week, "DEN", "KAN", 0.0125))
+	}
+	return builder.TeamData
+}
+
+func TestPredict(t *testing.T) {
+	games := []UpcomingGame{
+		{HomeTeam: "DEN", VisitingTeam: "KAN", Spread: -3},
+		{HomeTeam: "BUF", VisitingTeam: "NWE", Spread: 3},
+		{HomeTeam: "DEN", VisitingTeam: "SEA", Spread: 1},
+		// A game without a line would have an edge of over a thousand points.
+		{HomeTeam: "KAN", VisitingTeam: "NWE", Spread: NOSPREAD},
+	}
+	report := Predict(reportTeamData(), games, EstSpreadPredictor{})
+	if len(report) != 2 {
+		t.Fatalf("We got an unexpected result: %v instead of %v", len(report), 2)
+	}
+	if report[0].HomeTeam != "BUF" || report[0].Pick() != "NWE" || report[0].Edge >= 0 {
+		t.Errorf("We got an unexpected result: %+v, the biggest edge should be on NWE", report[0])
+	}
+	if math.Abs(report[0].MarketWP-WinProbability(0, 3, STDDEV)) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", report[0].MarketWP, WinProbability(0, 3, STDDEV))
+	}
+	if report[1].Pick() != "DEN" || report[1].Edge <= 0 {
+		t.Errorf("We got an unexpected result: %+v, the edge should be on DEN", report[1])
+	}
+	results := []GameResult{{HomeTeam: "BUF", VisitingTeam: "NWE", Spread: 3, HomeScore: 10}, {HomeTeam: "KAN", VisitingTeam: "NWE", Spread: NOSPREAD}}
+	if fromResults := PredictGameResults(reportTeamData(), results, EstSpreadPredictor{}); len(fromResults) != 1 || fromResults[0] != report[0] {
+		t.Errorf("We got an unexpected result: %+v instead of %+v", fromResults, report[:1])
+	}
+}
+
+func TestForGame(t *testing.T) {
+	TeamData := reportTeamData()
+	TeamData["DEN"][SPREAD] = 7
+	result := TeamData.ForGame(UpcomingGame{HomeTeam: "DEN", VisitingTeam: "SEA", Spread: -2.5})
+	if len(result) != 2 || result["DEN"][SPREAD] != -2.5 || result["SEA"][SPREAD] != 2.5 || math.Abs(result["DEN"][WPADJUST]-0.05) > 1e-9 {
+		t.Errorf("We got an unexpected result: %v, DEN and SEA should have this game's line", result)
+	}
+	if TeamData["DEN"][SPREAD] != 7 {
+		t.Errorf("We got an unexpected result: %v instead of %v", TeamData["DEN"][SPREAD], 7)
+	}
+}
+
+func TestPredictionReportWriters(t *testing.T) {
+	report := PredictionReport{{HomeTeam: "BUF", VisitingTeam: "NWE", MarketSpread: 3, AdjustedSpread: 7.5, MarketWP: 0.41, AdjustedWP: 0.29, Edge: -4.5}}
+	var buf bytes.Buffer
+	report.WriteCSV(&buf)
+	expected := "Home,Visitor,Market Spread,Adjusted Spread,Market WP,Adjusted WP,Edge,Pick\nBUF,NWE,3.0,7.5,0.410,0.290,-4.5,NWE\n"
+	if buf.String() != expected {
+		t.Errorf("We got an unexpected result: %q instead of %q", buf.String(), expected)
+	}
+	buf.Reset()
+	report.WriteMarkdown(&buf)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || lines[2] != "| BUF | NWE | 3.0 | 7.5 | 0.410 | 0.290 | -4.5 | NWE |" {
+		t.Errorf("We got an unexpected result: %q", buf.String())
+	}
+	buf.Reset()
+	report.WriteJSON(&buf)
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil || len(rows) != 1 || rows[0]["pick"] != "NWE" {
+		t.Errorf("We got an unexpected result: %v %v", buf.String(), err)
+	}
+	buf.Reset()
+	report.WriteText(&buf)
+	if !strings.HasPrefix(buf.String(), "Home") || strings.Count(buf.String(), "\n") != 2 {
+		t.Errorf("We got an unexpected result: %q", buf.String())
+	}
+	if err := report.WriteMarkdown(failingWriter{}); err == nil {
+		t.Errorf("We should get the writer's error from WriteMarkdown")
+	}
+	if err := report.WriteText(failingWriter{}); err == nil {
+		t.Errorf("We should get the writer's error from WriteText")
+	}
+}
+
+// A writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("the disk is full")
+}
@@ -0,0 +1,175 @@
+// Package rpc serves the prediction engine over gRPC for the services that speak it.
+// The service is defined in nflwp.proto, and nflwp.pb.go and nflwp_grpc.pb.go are generated from it.
+//
+// To serve a season database:
+//
+//	Store, err := storage.Open("nflwp.db")
+//	...
+//	Server := grpc.NewServer()
+//	rpc.RegisterPredictionEngineServer(Server, rpc.NewEngine(Store))
+//	Server.Serve(Listener)
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative nflwp.proto
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+
+	"github.com/thedadams/nflwp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A Store is where the Engine gets its season data. *storage.Store is one, and so is a server.Cache.
+// LoadTeamData returns an empty AllTeamData for a week that hasn't been saved, the same as the storage package.
+type Store interface {
+	LoadTeamData(Season string, Week int) (nflwp.AllTeamData, error)
+	LatestWeek(Season string) (int, error)
+	LoadGames(Season string, Week int) ([]nflwp.GameResult, error)
+}
+
+// An Engine is the PredictionEngine service on top of the nflwp package and a Store.
+// Predictors are the predictors StreamWeeklyPredictions can use, with the first one the default.
+type Engine struct {
+	UnimplementedPredictionEngineServer
+	Store      Store
+	Predictors []nflwp.Predictor
+}
+
+// Make an Engine for the Store, with the ensemble of the default predictors followed by the default predictors themselves.
+func NewEngine(Data Store) *Engine {
+	return &Engine{Store: Data, Predictors: append([]nflwp.Predictor{nflwp.NewEnsemblePredictor()}, nflwp.DefaultPredictors()...)}
+}
+
+// The sport with the given name, or the NFL if Name is "".
+func getSport(Name string) (nflwp.Sport, error) {
+	if Name == "" {
+		return nflwp.NFL, nil
+	}
+	if ThisSport, ok := nflwp.GetSport(Name); ok {
+		return ThisSport, nil
+	}
+	return nflwp.Sport{}, status.Errorf(codes.InvalidArgument, "there is no sport %q", Name)
+}
+
+// The status for an error from the Store: NotFound if there is no such row, like the HTTP server's 404, and Internal otherwise.
+func storeError(What string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(codes.NotFound, "couldn't load %v: %v", What, err)
+	}
+	return status.Errorf(codes.Internal, "couldn't load %v: %v", What, err)
+}
+
+func (e *Engine) WinProbability(ctx context.Context, Request *WinProbabilityRequest) (*WinProbabilityResponse, error) {
+	ThisSport, err := getSport(Request.GetSport())
+	if err != nil {
+		return nil, err
+	}
+	HomeWP := nflwp.WinProbability(0, Request.GetSpread(), ThisSport.MarginStdDev)
+	return &WinProbabilityResponse{HomeWp: HomeWP, VisitingWp: 1 - HomeWP}, nil
+}
+
+func (e *Engine) SpreadFromProbability(ctx context.Context, Request *SpreadFromProbabilityRequest) (*SpreadFromProbabilityResponse, error) {
+	ThisSport, err := getSport(Request.GetSport())
+	if err != nil {
+		return nil, err
+	}
+	if Request.GetHomeWp() <= 0 || Request.GetHomeWp() >= 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "the win probability has to be between 0 and 1, got %v", Request.GetHomeWp())
+	}
+	return &SpreadFromProbabilityResponse{Spread: nflwp.GuessSpread(Request.GetHomeWp(), ThisSport.MarginStdDev)}, nil
+}
+
+func (e *Engine) LiveWinProbability(ctx context.Context, Request *LiveWinProbabilityRequest) (*WinProbabilityResponse, error) {
+	ThisSport, err := getSport(Request.GetSport())
+	if err != nil {
+		return nil, err
+	}
+	Remaining := ThisSport.GameMinutes()
+	if Request.GetClock() != "" {
+		var ok bool
+		if Remaining, ok = ThisSport.ParseGameClock(Request.GetClock()); !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "can't read the clock %q", Request.GetClock())
+		}
+	} else if Request.MinutesRemaining != nil {
+		Remaining = Request.GetMinutesRemaining()
+	}
+	if Remaining < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "the minutes remaining can't be negative, got %v", Remaining)
+	}
+	HomeWP := ThisSport.LiveWinProbability(Request.GetMargin(), Request.GetSpread(), Remaining)
+	return &WinProbabilityResponse{HomeWp: HomeWP, VisitingWp: 1 - HomeWP}, nil
+}
+
+func (e *Engine) GetTeamRatings(ctx context.Context, Request *TeamRatingsRequest) (*TeamRatingsResponse, error) {
+	Week := int(Request.GetWeek())
+	var err error
+	if Week < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "the week can't be negative, got %v", Week)
+	}
+	if Week == 0 {
+		if Week, err = e.Store.LatestWeek(Request.GetSeason()); err != nil {
+			return nil, storeError("the season", err)
+		}
+	}
+	TeamData, err := e.Store.LoadTeamData(Request.GetSeason(), Week)
+	if err != nil {
+		return nil, storeError("the season", err)
+	}
+	if len(TeamData) == 0 {
+		return nil, status.Errorf(codes.NotFound, "there is no data for week %v of %v", Week, Request.GetSeason())
+	}
+	Response := &TeamRatingsResponse{Season: Request.GetSeason(), Week: int32(Week)}
+	for _, Team := range TeamData.Teams() {
+		val := TeamData[Team]
+		if Team == "BYE" || val[nflwp.GAMESPLAYED] == 0 {
+			continue
+		}
+		Rating := &TeamRating{Team: Team, GamesPlayed: val[nflwp.GAMESPLAYED], GamesWon: val[nflwp.GAMESWON],
+			Wpadjust: val[nflwp.WPADJUST] / val[nflwp.GAMESPLAYED], StraightWpadjust: val[nflwp.STRAIGHTWPADJUST] / val[nflwp.GAMESPLAYED]}
+		if val[nflwp.GAMESPLAYED] > 1 {
+			Rating.OppWpadjust = val[nflwp.OPPWPADJUST] / (val[nflwp.GAMESPLAYED] - 1)
+		}
+		Response.Ratings = append(Response.Ratings, Rating)
+	}
+	sort.SliceStable(Response.Ratings, func(i, j int) bool { return Response.Ratings[i].Wpadjust > Response.Ratings[j].Wpadjust })
+	return Response, nil
+}
+
+// The week's games are predicted from the season data saved after the week before, against the lines saved with the games.
+func (e *Engine) StreamWeeklyPredictions(Request *WeeklyPredictionsRequest, Stream grpc.ServerStreamingServer[Prediction]) error {
+	if Request.GetWeek() < 1 {
+		return status.Errorf(codes.InvalidArgument, "the week has to be at least 1, got %v", Request.GetWeek())
+	}
+	ThisSport, err := getSport(Request.GetSport())
+	if err != nil {
+		return err
+	}
+	Guess, ok := nflwp.FindPredictor(e.Predictors, Request.GetPredictor())
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "there is no predictor %q", Request.GetPredictor())
+	}
+	Results, err := e.Store.LoadGames(Request.GetSeason(), int(Request.GetWeek()))
+	if err != nil {
+		return storeError("the games", err)
+	}
+	if len(Results) == 0 {
+		return status.Errorf(codes.NotFound, "there are no games in week %v of %v", Request.GetWeek(), Request.GetSeason())
+	}
+	TeamData, err := e.Store.LoadTeamData(Request.GetSeason(), int(Request.GetWeek())-1)
+	if err != nil {
+		return storeError("the season", err)
+	}
+	for _, val := range nflwp.PredictGameResultsForSport(TeamData, Results, Guess, ThisSport) {
+		err = Stream.Send(&Prediction{Home: val.HomeTeam, Visitor: val.VisitingTeam, MarketSpread: val.MarketSpread, AdjustedSpread: val.AdjustedSpread,
+			MarketWp: val.MarketWP, AdjustedWp: val.AdjustedWP, Edge: val.Edge, Pick: val.Pick()})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/thedadams/nflwp"
+	"github.com/thedadams/nflwp/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// A client for an Engine on a Store where NWE and PIT have played twice by week 2 and NWE hosts PIT in week 3.
+func testClient(t *testing.T) PredictionEngineClient {
+	s, err := storage.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	teamData := nflwp.NewAllTeamData()
+	teamData["NWE"] = nflwp.NewTeamData()
+	teamData["NWE"][nflwp.WPADJUST] = 0.2
+	teamData["NWE"][nflwp.STRAIGHTWPADJUST] = 0.1
+	teamData["NWE"][nflwp.OPPWPADJUST] = 0.05
+	teamData["NWE"][nflwp.GAMESPLAYED] = 2
+	teamData["NWE"][nflwp.GAMESWON] = 2
+	teamData["PIT"] = nflwp.NewTeamData()
+	teamData["PIT"][nflwp.WPADJUST] = -0.2
+	teamData["PIT"][nflwp.STRAIGHTWPADJUST] = -0.1
+	teamData["PIT"][nflwp.GAMESPLAYED] = 2
+	teamData["BYE"] = nflwp.NewTeamData()
+	if err = s.SaveTeamData("2015", 2, teamData); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.SaveGame(nflwp.GameResult{Link: "/boxscores/201509270nwe.htm", Date: "20150927", Season: "2015", Week: 3, HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -3}); err != nil {
+		t.Fatal(err)
+	}
+	client, stop, err := NewInProcessClient(NewEngine(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(stop)
+	return client
+}
+
+func checkCode(t *testing.T, err error, expected codes.Code) {
+	if status.Code(err) != expected {
+		t.Errorf("We got an unexpected result: %v instead of %v", err, expected)
+	}
+}
+
+func TestWinProbabilities(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+	wp, err := client.WinProbability(ctx, &WinProbabilityRequest{Spread: -7})
+	if err != nil || math.Abs(wp.HomeWp-nflwp.WinProbability(0, -7, nflwp.STDDEV)) > 1e-9 || math.Abs(wp.HomeWp+wp.VisitingWp-1) > 1e-9 {
+		t.Errorf("We got an unexpected result: %v %v", wp, err)
+	}
+	if wp, err = client.WinProbability(ctx, &WinProbabilityRequest{Spread: -7, Sport: "ncaaf"}); err != nil || wp.HomeWp != nflwp.WinProbability(0, -7, nflwp.NCAAF.MarginStdDev) {
+		t.Errorf("We got an unexpected result: %v %v", wp, err)
+	}
+	_, err = client.WinProbability(ctx, &WinProbabilityRequest{Sport: "cricket"})
+	checkCode(t, err, codes.InvalidArgument)
+
+	spread, err := client.SpreadFromProbability(ctx, &SpreadFromProbabilityRequest{HomeWp: 0.7})
+	if err != nil || spread.Spread != nflwp.GuessSpread(0.7, nflwp.STDDEV) {
+		t.Errorf("We got an unexpected result: %v %v", spread, err)
+	}
+	_, err = client.SpreadFromProbability(ctx, &SpreadFromProbabilityRequest{HomeWp: 1})
+	checkCode(t, err, codes.InvalidArgument)
+
+	requests := []*LiveWinProbabilityRequest{{Spread: 3}, {Spread: 3, Margin: 7, MinutesRemaining: proto.Float64(30)}, {Spread: 3, Margin: 7, Clock: "Q3 15:00"}, {Spread: 3, Margin: 7, MinutesRemaining: proto.Float64(0)}}
+	expectedResults := []float64{nflwp.WinProbability(0, 3, nflwp.STDDEV), nflwp.LiveWinProbability(7, 3, 30), nflwp.LiveWinProbability(7, 3, 30), 1}
+	for i := 0; i < len(requests); i++ {
+		wp, err = client.LiveWinProbability(ctx, requests[i])
+		if err != nil || math.Abs(wp.HomeWp-expectedResults[i]) > 1e-9 {
+			t.Errorf("We got an unexpected result: %v %v instead of %v", wp, err, expectedResults[i])
+		}
+	}
+	_, err = client.LiveWinProbability(ctx, &LiveWinProbabilityRequest{Clock: "soon"})
+	checkCode(t, err, codes.InvalidArgument)
+	_, err = client.LiveWinProbability(ctx, &LiveWinProbabilityRequest{MinutesRemaining: proto.Float64(-1)})
+	checkCode(t, err, codes.InvalidArgument)
+}
+
+func TestGetTeamRatings(t *testing.T) {
+	client := testClient(t)
+	ratings, err := client.GetTeamRatings(context.Background(), &TeamRatingsRequest{Season: "2015"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// BYE hasn't played, so it isn't rated.
+	if ratings.Week != 2 || len(ratings.Ratings) != 2 || ratings.Ratings[0].Team != "NWE" || ratings.Ratings[1].Team != "PIT" {
+		t.Fatalf("We got an unexpected result: %v", ratings)
+	}
+	expected := &TeamRating{Team: "NWE", Wpadjust: 0.1, StraightWpadjust: 0.05, OppWpadjust: 0.05, GamesPlayed: 2, GamesWon: 2}
+	if !proto.Equal(ratings.Ratings[0], expected) {
+		t.Errorf("We got an unexpected result: %v instead of %v", ratings.Ratings[0], expected)
+	}
+	_, err = client.GetTeamRatings(context.Background(), &TeamRatingsRequest{Season: "2015", Week: 1})
+	checkCode(t, err, codes.NotFound)
+}
+
+// A Store that has nothing saved and says so with sql.ErrNoRows.
+type missingStore struct{}
+
+func (missingStore) LoadTeamData(Season string, Week int) (nflwp.AllTeamData, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (missingStore) LatestWeek(Season string) (int, error) {
+	return 0, sql.ErrNoRows
+}
+
+func (missingStore) LoadGames(Season string, Week int) ([]nflwp.GameResult, error) {
+	return nil, sql.ErrNoRows
+}
+
+func TestMissingRowsAreNotFound(t *testing.T) {
+	client, stop, err := NewInProcessClient(NewEngine(missingStore{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	_, err = client.GetTeamRatings(context.Background(), &TeamRatingsRequest{Season: "2015"})
+	checkCode(t, err, codes.NotFound)
+	_, err = client.GetTeamRatings(context.Background(), &TeamRatingsRequest{Season: "2015", Week: 2})
+	checkCode(t, err, codes.NotFound)
+	stream, err := client.StreamWeeklyPredictions(context.Background(), &WeeklyPredictionsRequest{Season: "2015", Week: 3})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	checkCode(t, err, codes.NotFound)
+}
+
+func TestStreamWeeklyPredictions(t *testing.T) {
+	client := testClient(t)
+	stream, err := client.StreamWeeklyPredictions(context.Background(), &WeeklyPredictionsRequest{Season: "2015", Week: 3, Predictor: "GuessBoth"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var predictions []*Prediction
+	for {
+		prediction, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		predictions = append(predictions, prediction)
+	}
+	teamData := nflwp.NewAllTeamData()
+	teamData["NWE"] = []float64{0.2, 0.1, 2, 2, 0.05, 0, 0}
+	teamData["PIT"] = []float64{-0.2, -0.1, 2, 0, 0, 0, 0}
+	expected := nflwp.Predict(teamData, []nflwp.UpcomingGame{{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -3}}, nflwp.GuessBothPredictor{})[0]
+	if len(predictions) != 1 || predictions[0].AdjustedSpread != expected.AdjustedSpread || predictions[0].Edge != expected.Edge || predictions[0].Pick != expected.Pick() {
+		t.Fatalf("We got an unexpected result: %v instead of %+v", predictions, expected)
+	}
+	// The market's win probability comes from the request's sport.
+	stream, err = client.StreamWeeklyPredictions(context.Background(), &WeeklyPredictionsRequest{Season: "2015", Week: 3, Predictor: "GuessBoth", Sport: "NCAAF"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prediction, err := stream.Recv(); err != nil || prediction.MarketWp != nflwp.WinProbability(0, -3, nflwp.NCAAF.MarginStdDev) {
+		t.Errorf("We got an unexpected result: %v %v", prediction, err)
+	}
+	for _, request := range []*WeeklyPredictionsRequest{{Season: "2015", Week: 0}, {Season: "2015", Week: 3, Predictor: "Magic"}, {Season: "2015", Week: 5},
+		{Season: "2015", Week: 3, Sport: "Cricket"}} {
+		stream, err = client.StreamWeeklyPredictions(context.Background(), request)
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if request.Week == 5 {
+			checkCode(t, err, codes.NotFound)
+		} else {
+			checkCode(t, err, codes.InvalidArgument)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package nflwp
+
+import (
+	"math"
+)
+
+// Given the games played so far, jointly solve for every team's opponent-adjusted WPADJUST rating
+// the same way the Simple Rating System works for point margins:
+// a team's rating is its average WPADJUST plus the average rating of the teams it played.
+// We iterate until no rating moves more than Tolerance or we hit MaxIterations.
+// Each step only moves halfway to the new ratings; otherwise two teams that have only played each other flip back and forth forever.
+// The ratings are centered so the league average is zero.
+//
+// OPPWPADJUST is replaced with each team's strength of schedule, the average rating of its opponents,
+// scaled by GAMESPLAYED-1 like GetTeamDataForWeek leaves it. This doesn't depend on the order the games were processed.
+// The ratings are returned along with whether they converged.
+// The History's opponents are NFL teams; use SolveOpponentAdjustmentForSport with the SeasonBuilder's Sport otherwise.
+func (a AllTeamData) SolveOpponentAdjustment(History TeamHistory, Tolerance float64, MaxIterations int) (map[string]float64, bool) {
+	return a.SolveOpponentAdjustmentForSport(History, Tolerance, MaxIterations, NFL)
+}
+
+// Like SolveOpponentAdjustment, but the opponents in the History are decoded with the sport's TeamRegistry.
+func (a AllTeamData) SolveOpponentAdjustmentForSport(History TeamHistory, Tolerance float64, MaxIterations int, ThisSport Sport) (map[string]float64, bool) {
+	Averages := make(map[string]float64)
+	Opponents := make(map[string][]string)
+	for Team, Games := range History {
+		if len(Games) == 0 {
+			continue
+		}
+		Averages[Team] = CumulativeMean{}.Aggregate(Team, WPADJUST, History.Values(Team, WPADJUST))
+		for _, val := range Games {
+			Opponents[Team] = append(Opponents[Team], ThisSport.Teams.Abbr(val[PLAYINGTHISWEEK]))
+		}
+	}
+	Ratings := make(map[string]float64)
+	for key, val := range Averages {
+		Ratings[key] = val
+	}
+	Converged := false
+	for count := 0; count < MaxIterations && !Converged; count++ {
+		Schedule := strengthOfSchedule(Opponents, Ratings)
+		NewRatings := make(map[string]float64)
+		Mean := 0.0
+		for Team := range Ratings {
+			NewRatings[Team] = (Ratings[Team] + Averages[Team] + Schedule[Team]) / 2
+			Mean += NewRatings[Team]
+		}
+		Mean /= float64(len(NewRatings))
+		Converged = true
+		for Team := range NewRatings {
+			NewRatings[Team] -= Mean
+			if math.Abs(NewRatings[Team]-Ratings[Team]) > Tolerance {
+				Converged = false
+			}
+		}
+		Ratings = NewRatings
+	}
+	// The schedules are found again from the ratings we return, whether or not they converged.
+	for Team, val := range strengthOfSchedule(Opponents, Ratings) {
+		if _, ok := a[Team]; ok {
+			a[Team][OPPWPADJUST] = val * (a[Team][GAMESPLAYED] - 1)
+		}
+	}
+	return Ratings, Converged
+}
+
+// The average rating of each team's opponents.
+func strengthOfSchedule(Opponents map[string][]string, Ratings map[string]float64) map[string]float64 {
+	Schedule := make(map[string]float64)
+	for Team, Opps := range Opponents {
+		for _, Opp := range Opps {
+			Schedule[Team] += Ratings[Opp]
+		}
+		Schedule[Team] /= float64(len(Opps))
+	}
+	return Schedule
+}
@@ -0,0 +1,66 @@
+package nflwp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSolveOpponentAdjustment(t *testing.T) {
+	games := [][]string{{"NWE", "BUF"}, {"NYJ", "MIA"}, {"NWE", "NYJ"}, {"BUF", "MIA"}, {"MIA", "NWE"}, {"BUF", "NYJ"}}
+	adjusts := []float64{0.1, -0.05, 0.2, 0.15, -0.3, 0.05}
+	builder := NewSeasonBuilder(nil)
+	for i := 0; i < len(games); i++ {
+		builder.AddGameResult(testResult(i+1, games[i][0], games[i][1], adjusts[i]))
+	}
+	ratings, converged := builder.TeamData.SolveOpponentAdjustment(builder.History, 1e-9, 1000)
+	if !converged {
+		t.Fatalf("The ratings did not converge: %v", ratings)
+	}
+	// Every team played everyone once, so each rating is its average plus the average of the other three ratings.
+	for team := range ratings {
+		opps := 0.0
+		for _, game := range builder.History[team] {
+			opps += ratings[GetTeamAbbrFromFloat(game[PLAYINGTHISWEEK])]
+		}
+		opps /= 3
+		average := builder.TeamData[team][WPADJUST] / builder.TeamData[team][GAMESPLAYED]
+		if math.Abs(ratings[team]-average-opps) > 0.0005 {
+			t.Errorf("We got an unexpected result for %v: %v instead of %v", team, ratings[team], average+opps)
+		}
+		if math.Abs(builder.TeamData[team][OPPWPADJUST]/2-opps) > 0.0005 {
+			t.Errorf("We got an unexpected result for %v: %v instead of %v", team, builder.TeamData[team][OPPWPADJUST]/2, opps)
+		}
+	}
+}
+
+func TestSolveOpponentAdjustmentStopped(t *testing.T) {
+	games := [][]string{{"NWE", "BUF"}, {"NYJ", "MIA"}, {"NWE", "NYJ"}, {"BUF", "MIA"}}
+	adjusts := []float64{0.1, -0.05, 0.2, 0.15}
+	// Whether or not the ratings converged, OPPWPADJUST is the schedule the returned ratings give.
+	for _, iterations := range []int{0, 1} {
+		builder := NewSeasonBuilder(nil)
+		for i := 0; i < len(games); i++ {
+			builder.AddGameResult(testResult(i+1, games[i][0], games[i][1], adjusts[i]))
+		}
+		ratings, _ := builder.TeamData.SolveOpponentAdjustment(builder.History, 1e-9, iterations)
+		for team := range ratings {
+			opps := 0.0
+			for _, game := range builder.History[team] {
+				opps += ratings[GetTeamAbbrFromFloat(game[PLAYINGTHISWEEK])]
+			}
+			opps /= 2
+			if math.Abs(builder.TeamData[team][OPPWPADJUST]-opps) > 1e-12 {
+				t.Errorf("We got an unexpected result for %v after %v iterations: %v instead of %v", team, iterations, builder.TeamData[team][OPPWPADJUST], opps)
+			}
+		}
+	}
+}
+
+func TestSolveOpponentAdjustmentTwoTeams(t *testing.T) {
+	builder := NewSeasonBuilder(nil)
+	builder.AddGameResult(testResult(1, "NWE", "BUF", 0.2))
+	ratings, converged := builder.TeamData.SolveOpponentAdjustment(builder.History, 1e-9, 1000)
+	if !converged || math.Abs(ratings["NWE"]-ratings["BUF"]-0.2) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v", ratings)
+	}
+}
@@ -0,0 +1,206 @@
+package nflwp
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// The names of the TeamData metrics, in the order of the constants.
+var MetricNames = []string{"WPADJUST", "STRAIGHTWPADJUST", "GAMESPLAYED", "GAMESWON", "OPPWPADJUST", "SPREAD", "PLAYINGTHISWEEK"}
+
+// Return the metric constant for the name, or -1 if there isn't one.
+func GetMetricFromName(Name string) int {
+	for i, val := range MetricNames {
+		if val == Name {
+			return i
+		}
+	}
+	return -1
+}
+
+// The teams in the AllTeamData in alphabetical order.
+func (a AllTeamData) Teams() []string {
+	Teams := make([]string, 0, len(a))
+	for key := range a {
+		Teams = append(Teams, key)
+	}
+	sort.Strings(Teams)
+	return Teams
+}
+
+// The PLAYINGTHISWEEK code for an opponent in the sport, with "" as no opponent.
+// An opponent the sport's TeamRegistry doesn't know is an error rather than a bye.
+func opponentCode(ThisSport Sport, Opponent string) (float64, error) {
+	if Opponent == "" {
+		return 0, nil
+	}
+	Code, ok := ThisSport.Teams.Code(Opponent)
+	if !ok {
+		return 0, fmt.Errorf("we don't know the opponent %q", Opponent)
+	}
+	return Code, nil
+}
+
+// The opponent for a PLAYINGTHISWEEK code in the sport. A code without a team is an error.
+func opponentAbbr(ThisSport Sport, Code float64) (string, error) {
+	Opponent := ThisSport.Teams.Abbr(Code)
+	if Opponent == "" {
+		return "", fmt.Errorf("no team has the opponent code %v", Code)
+	}
+	return Opponent, nil
+}
+
+// MarshalJSON writes each team as an object of named metrics, with PLAYINGTHISWEEK as the opponent's abbreviation.
+// The opponents are NFL teams, see MarshalJSONForSport.
+func (a AllTeamData) MarshalJSON() ([]byte, error) {
+	return a.MarshalJSONForSport(NFL)
+}
+
+// Like MarshalJSON, but the opponents are decoded with the sport's TeamRegistry.
+func (a AllTeamData) MarshalJSONForSport(ThisSport Sport) ([]byte, error) {
+	Teams := make(map[string]map[string]interface{}, len(a))
+	for key, val := range a {
+		Metrics := make(map[string]interface{}, len(MetricNames))
+		for i, Name := range MetricNames {
+			if i == PLAYINGTHISWEEK {
+				Opponent, err := opponentAbbr(ThisSport, val[i])
+				if err != nil {
+					return nil, fmt.Errorf("bad opponent for team %v: %v", key, err)
+				}
+				Metrics[Name] = Opponent
+			} else {
+				Metrics[Name] = val[i]
+			}
+		}
+		Teams[key] = Metrics
+	}
+	return json.Marshal(Teams)
+}
+
+// UnmarshalJSON reads what MarshalJSON writes. Metrics that are missing are left at zero.
+func (a *AllTeamData) UnmarshalJSON(Data []byte) error {
+	return a.UnmarshalJSONForSport(Data, NFL)
+}
+
+// Like UnmarshalJSON, but the opponents are coded with the sport's TeamRegistry.
+func (a *AllTeamData) UnmarshalJSONForSport(Data []byte, ThisSport Sport) error {
+	var Teams map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(Data, &Teams); err != nil {
+		return err
+	}
+	if *a == nil {
+		*a = NewAllTeamData()
+	}
+	for key, Metrics := range Teams {
+		TeamData := NewTeamData()
+		for Name, Raw := range Metrics {
+			Metric := GetMetricFromName(Name)
+			if Metric < 0 {
+				return fmt.Errorf("unknown metric %v for team %v", Name, key)
+			}
+			if Metric == PLAYINGTHISWEEK {
+				var Opponent string
+				if err := json.Unmarshal(Raw, &Opponent); err != nil {
+					return fmt.Errorf("bad opponent for team %v: %v", key, err)
+				}
+				Code, err := opponentCode(ThisSport, Opponent)
+				if err != nil {
+					return fmt.Errorf("bad opponent for team %v: %v", key, err)
+				}
+				TeamData[Metric] = Code
+			} else if err := json.Unmarshal(Raw, &TeamData[Metric]); err != nil {
+				return fmt.Errorf("bad %v for team %v: %v", Name, key, err)
+			}
+		}
+		(*a)[key] = TeamData
+	}
+	return nil
+}
+
+// Write the AllTeamData as CSV with a header row, one team per row in alphabetical order.
+// Like MarshalJSON, PLAYINGTHISWEEK is written as the opponent's abbreviation, and the opponents are NFL teams.
+func (a AllTeamData) WriteCSV(w io.Writer) error {
+	return a.WriteCSVForSport(w, NFL)
+}
+
+// Like WriteCSV, but the opponents are decoded with the sport's TeamRegistry.
+func (a AllTeamData) WriteCSVForSport(w io.Writer, ThisSport Sport) error {
+	cw := csv.NewWriter(w)
+	cw.Write(append([]string{"Team"}, MetricNames...))
+	for _, Team := range a.Teams() {
+		Row := []string{Team}
+		for i := range MetricNames {
+			if i == PLAYINGTHISWEEK {
+				Opponent, err := opponentAbbr(ThisSport, a[Team][i])
+				if err != nil {
+					return fmt.Errorf("bad opponent for team %v: %v", Team, err)
+				}
+				Row = append(Row, Opponent)
+			} else {
+				Row = append(Row, strconv.FormatFloat(a[Team][i], 'g', -1, 64))
+			}
+		}
+		cw.Write(Row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Read an AllTeamData written by WriteCSV. The columns can be in any order, but the header has to be there.
+func ReadAllTeamDataCSV(r io.Reader) (AllTeamData, error) {
+	return ReadAllTeamDataCSVForSport(r, NFL)
+}
+
+// Like ReadAllTeamDataCSV, but the opponents are coded with the sport's TeamRegistry.
+func ReadAllTeamDataCSVForSport(r io.Reader, ThisSport Sport) (AllTeamData, error) {
+	cr := csv.NewReader(r)
+	Header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	Columns := make([]int, len(Header))
+	TeamColumn := -1
+	for i, val := range Header {
+		if val == "Team" {
+			TeamColumn = i
+			Columns[i] = -1
+			continue
+		}
+		if Columns[i] = GetMetricFromName(val); Columns[i] < 0 {
+			return nil, fmt.Errorf("unknown column %v", val)
+		}
+	}
+	if TeamColumn < 0 {
+		return nil, fmt.Errorf("there is no Team column")
+	}
+	TeamData := NewAllTeamData()
+	for {
+		Row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		Line, _ := cr.FieldPos(0)
+		val := NewTeamData()
+		for i, Field := range Row {
+			if Columns[i] < 0 {
+				continue
+			}
+			if Columns[i] == PLAYINGTHISWEEK {
+				if val[Columns[i]], err = opponentCode(ThisSport, Field); err != nil {
+					return nil, fmt.Errorf("line %v: bad %v: %v", Line, Header[i], err)
+				}
+			} else if val[Columns[i]], err = strconv.ParseFloat(Field, 64); err != nil {
+				return nil, fmt.Errorf("line %v: bad %v: %v", Line, Header[i], err)
+			}
+		}
+		TeamData[Row[TeamColumn]] = val
+	}
+	return TeamData, nil
+}
@@ -0,0 +1,83 @@
+package nflwp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// NWE has won two of three games and hosts BUF this week, and BUF hasn't played.
+func serializeTeamData() AllTeamData {
+	builder := NewSeasonBuilder(nil)
+	builder.AddGameResult(testResult(1, "NWE", "NYJ", 0.123456789))
+	builder.AddGameResult(testResult(1, "MIA", "PIT", 0.3))
+	builder.AddGameResult(testResult(2, "MIA", "NWE", 0.25))
+	builder.AddGameResult(testResult(3, "NWE", "NYJ", 0.5))
+	teamData := builder.TeamData.ForGame(UpcomingGame{HomeTeam: "NWE", VisitingTeam: "BUF", Spread: -7})
+	teamData["BYE"] = builder.TeamData["BYE"]
+	return teamData
+}
+
+func compareTeamData(t *testing.T, result, expected AllTeamData) {
+	if len(result) != len(expected) {
+		t.Errorf("We got an unexpected result: %v teams instead of %v", len(result), len(expected))
+	}
+	for team, val := range expected {
+		for i := 0; i < TOTALDATAPOINTS; i++ {
+			if result[team][i] != val[i] {
+				t.Errorf("We got an unexpected result for %v %v: %v instead of %v", team, MetricNames[i], result[team][i], val[i])
+			}
+		}
+	}
+}
+
+func TestAllTeamDataJSON(t *testing.T) {
+	teamData := serializeTeamData()
+	data, err := json.Marshal(teamData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"PLAYINGTHISWEEK":"BUF"`) {
+		t.Errorf("We got an unexpected result: %s", data)
+	}
+	var result AllTeamData
+	if err = json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	compareTeamData(t, result, teamData)
+	if err = json.Unmarshal([]byte(`{"NWE":{"NOTAMETRIC":1}}`), &result); err == nil {
+		t.Errorf("We should not be able to read an unknown metric")
+	}
+	// An opponent we don't know would otherwise read as a bye.
+	if err = json.Unmarshal([]byte(`{"NWE":{"PLAYINGTHISWEEK":"XXX"}}`), &result); err == nil {
+		t.Errorf("We should not be able to read an unknown opponent")
+	}
+	teamData["NWE"][PLAYINGTHISWEEK] = 99
+	if _, err = json.Marshal(teamData); err == nil {
+		t.Errorf("We should not be able to write an opponent code without a team")
+	}
+}
+
+func TestAllTeamDataCSV(t *testing.T) {
+	teamData := serializeTeamData()
+	var buf bytes.Buffer
+	if err := teamData.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if lines[0] != "Team,WPADJUST,STRAIGHTWPADJUST,GAMESPLAYED,GAMESWON,OPPWPADJUST,SPREAD,PLAYINGTHISWEEK" || !strings.HasPrefix(lines[1], "BUF,") {
+		t.Errorf("We got an unexpected result: %q", buf.String())
+	}
+	result, err := ReadAllTeamDataCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareTeamData(t, result, teamData)
+	if _, err = ReadAllTeamDataCSV(strings.NewReader("Team,WPADJUST\nNWE,abc\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("We got an unexpected result: %v", err)
+	}
+	if _, err = ReadAllTeamDataCSV(strings.NewReader("Team,PLAYINGTHISWEEK\nNWE,XXX\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("We got an unexpected result: %v", err)
+	}
+}
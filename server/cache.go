@@ -0,0 +1,103 @@
+package server
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/thedadams/nflwp"
+)
+
+// A Cache is a Store that remembers what it loads from another Store, so repeated requests don't go back to the database.
+// What it hands out is shared, so callers mustn't change it. Call Clear after saving new data to the Store underneath.
+// The latest week and empty results aren't remembered, since they change as soon as more is saved.
+// The lock only guards the maps, so a slow load doesn't hold up requests for what is already cached.
+type Cache struct {
+	Store Store
+	lock  sync.Mutex
+	teams map[string]nflwp.AllTeamData
+	games map[string]*nflwp.GameResult
+	weeks map[string][]nflwp.GameResult
+	// Clear bumps this, so a load that started before a Clear doesn't put old data back.
+	generation int
+}
+
+// Make a Cache in front of the Store.
+func NewCache(Data Store) *Cache {
+	c := &Cache{Store: Data}
+	c.Clear()
+	return c
+}
+
+// Forget everything the Cache has loaded.
+func (c *Cache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.teams = make(map[string]nflwp.AllTeamData)
+	c.games = make(map[string]*nflwp.GameResult)
+	c.weeks = make(map[string][]nflwp.GameResult)
+	c.generation++
+}
+
+// Look something up under the lock, and return the generation to hand to store if it has to be loaded.
+func (c *Cache) lookup(Find func() bool) (int, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.generation, Find()
+}
+
+// Remember something that was loaded, unless the Cache was cleared since the load started.
+func (c *Cache) store(Generation int, Save func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if Generation == c.generation {
+		Save()
+	}
+}
+
+func weekKey(Season string, Week int) string {
+	return Season + "/" + strconv.Itoa(Week)
+}
+
+func (c *Cache) LoadTeamData(Season string, Week int) (nflwp.AllTeamData, error) {
+	var val nflwp.AllTeamData
+	Generation, ok := c.lookup(func() (ok bool) { val, ok = c.teams[weekKey(Season, Week)]; return })
+	if ok {
+		return val, nil
+	}
+	TeamData, err := c.Store.LoadTeamData(Season, Week)
+	if err == nil && len(TeamData) > 0 {
+		c.store(Generation, func() { c.teams[weekKey(Season, Week)] = TeamData })
+	}
+	return TeamData, err
+}
+
+func (c *Cache) LatestWeek(Season string) (int, error) {
+	return c.Store.LatestWeek(Season)
+}
+
+// Games that aren't found aren't remembered, so they show up once they are saved.
+func (c *Cache) LoadGame(Link string) (*nflwp.GameResult, error) {
+	var val *nflwp.GameResult
+	Generation, ok := c.lookup(func() (ok bool) { val, ok = c.games[Link]; return })
+	if ok {
+		return val, nil
+	}
+	Result, err := c.Store.LoadGame(Link)
+	if err == nil {
+		c.store(Generation, func() { c.games[Link] = Result })
+	}
+	return Result, err
+}
+
+func (c *Cache) LoadGames(Season string, Week int) ([]nflwp.GameResult, error) {
+	var val []nflwp.GameResult
+	Generation, ok := c.lookup(func() (ok bool) { val, ok = c.weeks[weekKey(Season, Week)]; return })
+	if ok {
+		return val, nil
+	}
+	Games, err := c.Store.LoadGames(Season, Week)
+	if err == nil && len(Games) > 0 {
+		c.store(Generation, func() { c.weeks[weekKey(Season, Week)] = Games })
+	}
+	return Games, err
+}
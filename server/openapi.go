@@ -0,0 +1,176 @@
+package server
+
+import (
+	"net/http"
+)
+
+// The OpenAPI 3 document describing the API, served at /openapi.json.
+const OpenAPI = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "nflwp",
+    "description": "Win probabilities, WP adjustment team ratings and spread predictions. Spreads and win probabilities are from the home team's point of view, so a negative spread means the home team is favored.",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/wp": {
+      "get": {
+        "summary": "The home team's win probability given the spread, the score and the clock",
+        "parameters": [
+          {"name": "spread", "in": "query", "required": true, "schema": {"type": "number"}, "description": "The home team's spread."},
+          {"name": "margin", "in": "query", "schema": {"type": "number", "default": 0}, "description": "The home team's lead."},
+          {"name": "clock", "in": "query", "schema": {"type": "string"}, "description": "Minutes left in the game, like 32.5, or the quarter and clock, like Q3 2:30 or OT 4:00. Kickoff if left out."}
+        ],
+        "responses": {
+          "200": {"description": "The win probability", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/WP"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      }
+    },
+    "/teams/{season}": {
+      "get": {
+        "summary": "Every team's season data after a week",
+        "parameters": [
+          {"$ref": "#/components/parameters/Season"},
+          {"name": "week", "in": "query", "schema": {"type": "integer", "minimum": 1}, "description": "The week. The latest saved week if left out."}
+        ],
+        "responses": {
+          "200": {"description": "The season data", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Teams"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/games/{id}/wp-series": {
+      "get": {
+        "summary": "A game's win probability chart next to what the spread alone gives",
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}, "description": "The box score page without the directory or extension, like 201509100nwe."}
+        ],
+        "responses": {
+          "200": {"description": "The chart", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Series"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/predictions/{season}/{week}": {
+      "get": {
+        "summary": "Predictions for a week's games from the season data after the week before, biggest edge first",
+        "parameters": [
+          {"$ref": "#/components/parameters/Season"},
+          {"name": "week", "in": "path", "required": true, "schema": {"type": "integer", "minimum": 1}},
+          {"name": "predictor", "in": "query", "schema": {"type": "string", "default": "Ensemble"}, "description": "Ensemble, GuessSpread, GuessWP, GuessOP, GuessBoth or EstSpread."}
+        ],
+        "responses": {
+          "200": {"description": "The predictions", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Predictions"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "Season": {"name": "season", "in": "path", "required": true, "schema": {"type": "string"}, "example": "2015"}
+    },
+    "responses": {
+      "BadRequest": {"description": "A parameter couldn't be read", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}},
+      "NotFound": {"description": "There is no data for the request", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+    },
+    "schemas": {
+      "Error": {
+        "type": "object",
+        "properties": {"error": {"type": "string"}}
+      },
+      "WP": {
+        "type": "object",
+        "properties": {
+          "spread": {"type": "number"},
+          "margin": {"type": "number"},
+          "remaining": {"type": "number", "description": "Minutes left in the game."},
+          "home_wp": {"type": "number"},
+          "visiting_wp": {"type": "number"}
+        }
+      },
+      "Teams": {
+        "type": "object",
+        "properties": {
+          "season": {"type": "string"},
+          "week": {"type": "integer"},
+          "teams": {
+            "type": "object",
+            "description": "Each team's metrics by abbreviation. The adjustments are totals over the games played.",
+            "additionalProperties": {
+              "type": "object",
+              "properties": {
+                "WPADJUST": {"type": "number"},
+                "STRAIGHTWPADJUST": {"type": "number"},
+                "GAMESPLAYED": {"type": "number"},
+                "GAMESWON": {"type": "number"},
+                "OPPWPADJUST": {"type": "number"},
+                "SPREAD": {"type": "number"},
+                "PLAYINGTHISWEEK": {"type": "string", "description": "The opponent's abbreviation."}
+              }
+            }
+          }
+        }
+      },
+      "Series": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "link": {"type": "string"},
+          "date": {"type": "string", "description": "YYYYMMDD"},
+          "home_team": {"type": "string"},
+          "visiting_team": {"type": "string"},
+          "spread": {"type": "number"},
+          "home_score": {"type": "number"},
+          "visiting_score": {"type": "number"},
+          "points": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "quarter": {"type": "number", "description": "5 in overtime."},
+                "remaining": {"type": "number", "description": "Minutes left in the game."},
+                "home_wp": {"type": "number"},
+                "spread_wp": {"type": "number", "description": "The win probability if the score had gone the way the spread said."},
+                "play": {"type": "string"}
+              }
+            }
+          }
+        }
+      },
+      "Predictions": {
+        "type": "object",
+        "properties": {
+          "season": {"type": "string"},
+          "week": {"type": "integer"},
+          "predictor": {"type": "string"},
+          "predictions": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "home": {"type": "string"},
+                "visitor": {"type": "string"},
+                "market_spread": {"type": "number"},
+                "adjusted_spread": {"type": "number"},
+                "market_wp": {"type": "number"},
+                "adjusted_wp": {"type": "number"},
+                "edge": {"type": "number", "description": "How many points better the home team is than the market thinks."},
+                "pick": {"type": "string"}
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(OpenAPI))
+}
@@ -0,0 +1,334 @@
+// Package server serves win probabilities, team ratings, game charts and predictions over HTTP as JSON,
+// so front-ends don't have to run the scrapers themselves.
+//
+// The endpoints are described by the OpenAPI document at /openapi.json. To serve a season database:
+//
+//	Store, err := storage.Open("nflwp.db")
+//	...
+//	http.ListenAndServe(":8080", server.New(server.NewCache(Store)))
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/thedadams/nflwp"
+)
+
+// A Store is where the server gets its season data. *storage.Store is one, and a Cache can sit in front of it.
+// LoadTeamData returns an empty AllTeamData for a week that hasn't been saved, and LoadGame returns sql.ErrNoRows
+// for a game that hasn't been saved, the same as the storage package.
+type Store interface {
+	LoadTeamData(Season string, Week int) (nflwp.AllTeamData, error)
+	LatestWeek(Season string) (int, error)
+	LoadGame(Link string) (*nflwp.GameResult, error)
+	LoadGames(Season string, Week int) ([]nflwp.GameResult, error)
+}
+
+// A Server answers the API's requests from a Store.
+// Sport is used for the win probabilities and game links, and Predictors are the predictors /predictions can use,
+// with the first one the default.
+type Server struct {
+	Store      Store
+	Sport      nflwp.Sport
+	Predictors []nflwp.Predictor
+	mux        *http.ServeMux
+}
+
+// Make a Server for NFL data in the Store, with the ensemble of the default predictors
+// followed by the default predictors themselves.
+func New(Data Store) *Server {
+	s := &Server{
+		Store:      Data,
+		Sport:      nflwp.NFL,
+		Predictors: append([]nflwp.Predictor{nflwp.NewEnsemblePredictor()}, nflwp.DefaultPredictors()...),
+		mux:        http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/wp", s.handleWP)
+	s.mux.HandleFunc("/teams/", s.handleTeams)
+	s.mux.HandleFunc("/games/", s.handleGame)
+	s.mux.HandleFunc("/predictions/", s.handlePredictions)
+	s.mux.HandleFunc("/openapi.json", handleOpenAPI)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%v isn't supported", r.Method))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// An Error is the body of every response that isn't a 200.
+type Error struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, Status int, Body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(Status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(Body); err != nil {
+		fmt.Println("Error: ", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, Status int, err error) {
+	writeJSON(w, Status, Error{Error: err.Error()})
+}
+
+// Write a 404 for missing data and a 500 for anything else.
+func writeStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	fmt.Println("Error: ", err)
+	writeError(w, http.StatusInternalServerError, errors.New("couldn't load the data"))
+}
+
+// Split the path after the prefix into its parts, or return false if it doesn't have Count of them.
+func pathParts(Path, Prefix string, Count int) ([]string, bool) {
+	Parts := strings.Split(strings.Trim(strings.TrimPrefix(Path, Prefix), "/"), "/")
+	if len(Parts) != Count {
+		return nil, false
+	}
+	for _, val := range Parts {
+		if val == "" {
+			return nil, false
+		}
+	}
+	return Parts, true
+}
+
+// A WPResponse is the answer to /wp. Everything is from the home team's point of view.
+type WPResponse struct {
+	Spread     float64 `json:"spread"`
+	Margin     float64 `json:"margin"`
+	Remaining  float64 `json:"remaining"`
+	HomeWP     float64 `json:"home_wp"`
+	VisitingWP float64 `json:"visiting_wp"`
+}
+
+// Given the clock from a request, return the minutes left in the game.
+// It is either minutes, like "32.5", or a quarter and clock, like "Q3 2:30" or "OT 4:00". It is kickoff if empty.
+func (s *Server) parseClock(Clock string) (float64, error) {
+	Clock = strings.TrimSpace(Clock)
+	if Clock == "" {
+		return s.Sport.GameMinutes(), nil
+	}
+	if Remaining, err := strconv.ParseFloat(Clock, 64); err == nil {
+		if Remaining < 0 {
+			return 0, fmt.Errorf("the clock can't be negative, got %v", Clock)
+		}
+		return Remaining, nil
+	}
+	if Remaining, ok := s.Sport.ParseGameClock(Clock); ok {
+		return Remaining, nil
+	}
+	return 0, fmt.Errorf("can't read the clock %q", Clock)
+}
+
+// GET /wp?spread=-3&margin=7&clock=Q3 10:00
+func (s *Server) handleWP(w http.ResponseWriter, r *http.Request) {
+	Query := r.URL.Query()
+	var Response WPResponse
+	var err error
+	if Query.Get("spread") == "" {
+		writeError(w, http.StatusBadRequest, errors.New("spread is required"))
+		return
+	}
+	if Response.Spread, err = strconv.ParseFloat(Query.Get("spread"), 64); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("can't read the spread %q", Query.Get("spread")))
+		return
+	}
+	if Query.Get("margin") != "" {
+		if Response.Margin, err = strconv.ParseFloat(Query.Get("margin"), 64); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("can't read the margin %q", Query.Get("margin")))
+			return
+		}
+	}
+	if Response.Remaining, err = s.parseClock(Query.Get("clock")); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	Response.HomeWP = s.Sport.LiveWinProbability(Response.Margin, Response.Spread, Response.Remaining)
+	Response.VisitingWP = 1 - Response.HomeWP
+	writeJSON(w, http.StatusOK, Response)
+}
+
+// A TeamsResponse is the answer to /teams/{season}. Teams is written by AllTeamData.MarshalJSONForSport for the Server's Sport,
+// since the opponents are coded with it, so read it with UnmarshalJSONForSport.
+type TeamsResponse struct {
+	Season string          `json:"season"`
+	Week   int             `json:"week"`
+	Teams  json.RawMessage `json:"teams"`
+}
+
+// GET /teams/{season}?week=3
+// Without a week, the latest saved week of the season is used.
+func (s *Server) handleTeams(w http.ResponseWriter, r *http.Request) {
+	Parts, ok := pathParts(r.URL.Path, "/teams/", 1)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("expected /teams/{season}"))
+		return
+	}
+	Response := TeamsResponse{Season: Parts[0]}
+	var err error
+	if Week := r.URL.Query().Get("week"); Week != "" {
+		if Response.Week, err = strconv.Atoi(Week); err != nil || Response.Week < 1 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("can't read the week %q", Week))
+			return
+		}
+	} else if Response.Week, err = s.Store.LatestWeek(Response.Season); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	TeamData, err := s.Store.LoadTeamData(Response.Season, Response.Week)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if len(TeamData) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("there is no data for week %v of %v", Response.Week, Response.Season))
+		return
+	}
+	// The opponents are coded with the server's sport, which AllTeamData.MarshalJSON doesn't know.
+	if Response.Teams, err = TeamData.MarshalJSONForSport(s.Sport); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Response)
+}
+
+// The ID of a game in the API is its box score page without the directory or extension, like "201509100nwe".
+func GameID(Link string) string {
+	return strings.TrimSuffix(path.Base(Link), path.Ext(Link))
+}
+
+// The box score link for a game ID, in the same directory and with the same extension as the sport's links.
+func (s *Server) gameLink(ID string) string {
+	Example := s.Sport.Source.GamePath("20000101", "team")
+	return path.Dir(Example) + "/" + ID + path.Ext(Example)
+}
+
+// A SeriesPoint is a play in a game's chart. HomeWP is the data source's win probability, and SpreadWP is what the spread
+// alone would give at the same point of the game if the score had gone the way the spread said.
+type SeriesPoint struct {
+	Quarter   float64 `json:"quarter"`
+	Remaining float64 `json:"remaining"`
+	HomeWP    float64 `json:"home_wp"`
+	SpreadWP  float64 `json:"spread_wp"`
+	Play      string  `json:"play"`
+}
+
+// A SeriesResponse is the answer to /games/{id}/wp-series.
+type SeriesResponse struct {
+	ID            string        `json:"id"`
+	Link          string        `json:"link"`
+	Date          string        `json:"date"`
+	HomeTeam      string        `json:"home_team"`
+	VisitingTeam  string        `json:"visiting_team"`
+	Spread        float64       `json:"spread"`
+	HomeScore     float64       `json:"home_score"`
+	VisitingScore float64       `json:"visiting_score"`
+	Points        []SeriesPoint `json:"points"`
+}
+
+// GET /games/{id}/wp-series
+func (s *Server) handleGame(w http.ResponseWriter, r *http.Request) {
+	Parts, ok := pathParts(r.URL.Path, "/games/", 2)
+	if !ok || Parts[1] != "wp-series" {
+		writeError(w, http.StatusNotFound, errors.New("expected /games/{id}/wp-series"))
+		return
+	}
+	Result, err := s.Store.LoadGame(s.gameLink(Parts[0]))
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	Response := SeriesResponse{ID: Parts[0], Link: Result.Link, Date: Result.Date, HomeTeam: Result.HomeTeam, VisitingTeam: Result.VisitingTeam,
+		Spread: Result.Spread, HomeScore: Result.HomeScore, VisitingScore: Result.VisitingScore, Points: make([]SeriesPoint, len(Result.Points))}
+	for i, val := range Result.Points {
+		// Overtime makes the game longer, so measure the time left against the length of the game at this play.
+		Total := val.TotalMinutes
+		if Total <= 0 {
+			Total = s.Sport.GameMinutes()
+		}
+		// The margin the spread expects by now.
+		Expected := -Result.Spread * (1 - val.Remaining/Total)
+		Response.Points[i] = SeriesPoint{Quarter: val.Quarter, Remaining: val.Remaining, HomeWP: val.HomeWP,
+			SpreadWP: s.Sport.LiveWinProbability(Expected, Result.Spread, val.Remaining*s.Sport.GameMinutes()/Total), Play: strings.Trim(val.PlayInfo, "\"")}
+	}
+	writeJSON(w, http.StatusOK, Response)
+}
+
+// A Prediction is a row of a PredictionReport.
+type Prediction struct {
+	HomeTeam       string  `json:"home"`
+	VisitingTeam   string  `json:"visitor"`
+	MarketSpread   float64 `json:"market_spread"`
+	AdjustedSpread float64 `json:"adjusted_spread"`
+	MarketWP       float64 `json:"market_wp"`
+	AdjustedWP     float64 `json:"adjusted_wp"`
+	Edge           float64 `json:"edge"`
+	Pick           string  `json:"pick"`
+}
+
+// A PredictionsResponse is the answer to /predictions/{season}/{week}, biggest edge first.
+type PredictionsResponse struct {
+	Season      string       `json:"season"`
+	Week        int          `json:"week"`
+	Predictor   string       `json:"predictor"`
+	Predictions []Prediction `json:"predictions"`
+}
+
+// GET /predictions/{season}/{week}?predictor=GuessBoth
+// The week's games are predicted from the season data saved after the week before, against the lines saved with the games.
+func (s *Server) handlePredictions(w http.ResponseWriter, r *http.Request) {
+	Parts, ok := pathParts(r.URL.Path, "/predictions/", 2)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("expected /predictions/{season}/{week}"))
+		return
+	}
+	Response := PredictionsResponse{Season: Parts[0], Predictions: []Prediction{}}
+	var err error
+	if Response.Week, err = strconv.Atoi(Parts[1]); err != nil || Response.Week < 1 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("can't read the week %q", Parts[1]))
+		return
+	}
+	Guess, ok := nflwp.FindPredictor(s.Predictors, r.URL.Query().Get("predictor"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("there is no predictor %q", r.URL.Query().Get("predictor")))
+		return
+	}
+	Response.Predictor = Guess.Name()
+	Results, err := s.Store.LoadGames(Response.Season, Response.Week)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if len(Results) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("there are no games in week %v of %v", Response.Week, Response.Season))
+		return
+	}
+	TeamData, err := s.Store.LoadTeamData(Response.Season, Response.Week-1)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	for _, val := range nflwp.PredictGameResultsForSport(TeamData, Results, Guess, s.Sport) {
+		Response.Predictions = append(Response.Predictions, Prediction{val.HomeTeam, val.VisitingTeam, val.MarketSpread,
+			val.AdjustedSpread, val.MarketWP, val.AdjustedWP, val.Edge, val.Pick()})
+	}
+	writeJSON(w, http.StatusOK, Response)
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thedadams/nflwp"
+	"github.com/thedadams/nflwp/storage"
+)
+
+// A Store with two weeks of data: NWE and PIT have played twice by week 2, and NWE hosts PIT again in week 3.
+func testStore(t *testing.T) *storage.Store {
+	s, err := storage.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	teamData := nflwp.NewAllTeamData()
+	teamData["NWE"] = nflwp.NewTeamData()
+	teamData["NWE"][nflwp.WPADJUST] = 0.2
+	teamData["NWE"][nflwp.STRAIGHTWPADJUST] = 0.2
+	teamData["NWE"][nflwp.GAMESPLAYED] = 2
+	teamData["PIT"] = nflwp.NewTeamData()
+	teamData["PIT"][nflwp.WPADJUST] = -0.2
+	teamData["PIT"][nflwp.STRAIGHTWPADJUST] = -0.2
+	teamData["PIT"][nflwp.GAMESPLAYED] = 2
+	if err = s.SaveTeamData("2015", 2, teamData); err != nil {
+		t.Fatal(err)
+	}
+	games := []nflwp.GameResult{
+		{Link: "/boxscores/201509100nwe.htm", Date: "20150910", Season: "2015", Week: 1, HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -7, HomeScore: 28, VisitingScore: 21,
+			Points: []nflwp.WPPoint{{HomeWP: 0.7, PlayInfo: "\"Q1 15:00\"", Quarter: 1, Remaining: 60, TotalMinutes: 60}, {HomeWP: 0.8, PlayInfo: "\"Q3 15:00\"", Quarter: 3, Remaining: 30, Elapsed: 30, TotalMinutes: 60},
+				{HomeWP: 0.6, PlayInfo: "\"OT 10:00\"", Quarter: 5, Remaining: 10, Elapsed: 65, TotalMinutes: 75}}},
+		{Link: "/boxscores/201509270nwe.htm", Date: "20150927", Season: "2015", Week: 3, HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -3},
+	}
+	for _, val := range games {
+		if err = s.SaveGame(val); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func get(t *testing.T, h http.Handler, URL string, Status int, Body interface{}) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, URL, nil))
+	if w.Code != Status {
+		t.Fatalf("We got an unexpected result for %v: %v instead of %v: %v", URL, w.Code, Status, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), Body); err != nil {
+		t.Fatalf("We couldn't read the response to %v: %v", URL, err)
+	}
+}
+
+func TestWP(t *testing.T) {
+	s := New(nil)
+	urls := []string{"/wp?spread=-7", "/wp?spread=3&margin=7&clock=30", "/wp?spread=3&margin=7&clock=Q3%2015:00", "/wp?spread=0&margin=-1&clock=0"}
+	expectedResults := []float64{nflwp.WinProbability(0, -7, nflwp.STDDEV), nflwp.LiveWinProbability(7, 3, 30), nflwp.LiveWinProbability(7, 3, 30), 0}
+	for i := 0; i < len(urls); i++ {
+		var response WPResponse
+		get(t, s, urls[i], http.StatusOK, &response)
+		if math.Abs(response.HomeWP-expectedResults[i]) > 1e-9 || math.Abs(response.HomeWP+response.VisitingWP-1) > 1e-9 {
+			t.Errorf("We got an unexpected result for %v: %v instead of %v", urls[i], response.HomeWP, expectedResults[i])
+		}
+	}
+	for _, url := range []string{"/wp", "/wp?spread=x", "/wp?spread=-3&margin=x", "/wp?spread=-3&clock=soon", "/wp?spread=-3&clock=-5"} {
+		var response Error
+		get(t, s, url, http.StatusBadRequest, &response)
+		if response.Error == "" {
+			t.Errorf("We expected an error message for %v", url)
+		}
+	}
+}
+
+func TestTeams(t *testing.T) {
+	store := testStore(t)
+	defer store.Close()
+	s := New(NewCache(store))
+	for _, url := range []string{"/teams/2015", "/teams/2015?week=2"} {
+		var response TeamsResponse
+		get(t, s, url, http.StatusOK, &response)
+		var teams nflwp.AllTeamData
+		if err := teams.UnmarshalJSONForSport(response.Teams, nflwp.NFL); err != nil {
+			t.Fatal(err)
+		}
+		if response.Week != 2 || len(teams) != 2 || teams["NWE"][nflwp.WPADJUST] != 0.2 {
+			t.Errorf("We got an unexpected result for %v: %+v", url, teams)
+		}
+	}
+	var response Error
+	get(t, s, "/teams/2015?week=1", http.StatusNotFound, &response)
+	get(t, s, "/teams/2015?week=x", http.StatusBadRequest, &response)
+	get(t, s, "/teams/", http.StatusNotFound, &response)
+}
+
+func TestWPSeries(t *testing.T) {
+	store := testStore(t)
+	defer store.Close()
+	s := New(NewCache(store))
+	var response SeriesResponse
+	get(t, s, "/games/201509100nwe/wp-series", http.StatusOK, &response)
+	if response.Link != "/boxscores/201509100nwe.htm" || response.HomeTeam != "NWE" || len(response.Points) != 3 || response.Points[1].Play != "Q3 15:00" {
+		t.Fatalf("We got an unexpected result: %+v", response)
+	}
+	// The spread line is the same as the adjusted starting probability the WP adjustments are measured against, overtime included.
+	for i, info := range []string{"\"Q1 15:00 x\"", "\"Q3 15:00 x\"", "\"OT 10:00 x\""} {
+		if expected := nflwp.FindAdjustedStartingProbability(-7, info, 0); math.Abs(response.Points[i].SpreadWP-expected) > 1e-9 {
+			t.Errorf("We got an unexpected result: %v instead of %v", response.Points[i].SpreadWP, expected)
+		}
+	}
+	var missing Error
+	get(t, s, "/games/201509100xxx/wp-series", http.StatusNotFound, &missing)
+	get(t, s, "/games/201509100nwe/chart", http.StatusNotFound, &missing)
+	if GameID(response.Link) != "201509100nwe" {
+		t.Errorf("We got an unexpected result: %v", GameID(response.Link))
+	}
+}
+
+func TestPredictions(t *testing.T) {
+	store := testStore(t)
+	defer store.Close()
+	s := New(store)
+	var response PredictionsResponse
+	get(t, s, "/predictions/2015/3?predictor=guessboth", http.StatusOK, &response)
+	if response.Predictor != "GuessBoth" || len(response.Predictions) != 1 {
+		t.Fatalf("We got an unexpected result: %+v", response)
+	}
+	teamData, _ := store.LoadTeamData("2015", 2)
+	expected := nflwp.Predict(teamData, []nflwp.UpcomingGame{{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -3}}, nflwp.GuessBothPredictor{})[0]
+	if row := response.Predictions[0]; row.AdjustedSpread != expected.AdjustedSpread || row.Edge != expected.Edge || row.Pick != expected.Pick() {
+		t.Errorf("We got an unexpected result: %+v instead of %+v", row, expected)
+	}
+	get(t, s, "/predictions/2015/3", http.StatusOK, &response)
+	if response.Predictor != "Ensemble" {
+		t.Errorf("We got an unexpected result: %v instead of %v", response.Predictor, "Ensemble")
+	}
+	// Nobody has played the games the predictors need before week 1.
+	get(t, s, "/predictions/2015/1", http.StatusOK, &response)
+	if len(response.Predictions) != 0 {
+		t.Errorf("We got an unexpected result: %+v", response.Predictions)
+	}
+	// The market's win probability comes from the server's sport.
+	s.Sport = nflwp.NCAAF
+	get(t, s, "/predictions/2015/3?predictor=guessboth", http.StatusOK, &response)
+	if expected := nflwp.WinProbability(0, -3, nflwp.NCAAF.MarginStdDev); len(response.Predictions) != 1 || response.Predictions[0].MarketWP != expected {
+		t.Errorf("We got an unexpected result: %+v instead of a market WP of %v", response.Predictions, expected)
+	} else if row := response.Predictions[0]; row.AdjustedWP != nflwp.WinProbability(0, row.AdjustedSpread, nflwp.NCAAF.MarginStdDev) {
+		t.Errorf("We got an unexpected result: %+v", row)
+	}
+	s.Sport = nflwp.NFL
+	var missing Error
+	get(t, s, "/predictions/2015/5", http.StatusNotFound, &missing)
+	get(t, s, "/predictions/2015/3?predictor=Magic", http.StatusBadRequest, &missing)
+}
+
+func TestOpenAPI(t *testing.T) {
+	var doc struct {
+		OpenAPI string                 `json:"openapi"`
+		Paths   map[string]interface{} `json:"paths"`
+	}
+	get(t, New(nil), "/openapi.json", http.StatusOK, &doc)
+	for _, path := range []string{"/wp", "/teams/{season}", "/games/{id}/wp-series", "/predictions/{season}/{week}"} {
+		if _, ok := doc.Paths[path]; !ok {
+			t.Errorf("We expected %v in the OpenAPI document", path)
+		}
+	}
+	w := httptest.NewRecorder()
+	New(nil).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/wp?spread=3", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("We got an unexpected result: %v instead of %v", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestCache(t *testing.T) {
+	store := testStore(t)
+	defer store.Close()
+	cache := NewCache(store)
+	if games, err := cache.LoadGames("2015", 1); err != nil || len(games) != 1 {
+		t.Fatalf("We got an unexpected result: %v %v", games, err)
+	}
+	if err := store.SaveGame(nflwp.GameResult{Link: "/boxscores/201509130chi.htm", Season: "2015", Week: 1, HomeTeam: "CHI", VisitingTeam: "GNB"}); err != nil {
+		t.Fatal(err)
+	}
+	if games, _ := cache.LoadGames("2015", 1); len(games) != 1 {
+		t.Errorf("We expected the cached week, got %v games", len(games))
+	}
+	cache.Clear()
+	if games, _ := cache.LoadGames("2015", 1); len(games) != 2 {
+		t.Errorf("We expected the new game after clearing, got %v games", len(games))
+	}
+	// Empty weeks and the latest week aren't remembered.
+	if games, _ := cache.LoadGames("2015", 5); len(games) != 0 {
+		t.Errorf("We got an unexpected result: %v games instead of %v", len(games), 0)
+	}
+	before, _ := cache.LatestWeek("2015")
+	if err := store.SaveGame(nflwp.GameResult{Link: "/boxscores/201510110nwe.htm", Season: "2015", Week: 5, HomeTeam: "NWE", VisitingTeam: "DAL"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveTeamData("2015", before+1, nflwp.AllTeamData{"NWE": nflwp.NewTeamData()}); err != nil {
+		t.Fatal(err)
+	}
+	if games, _ := cache.LoadGames("2015", 5); len(games) != 1 {
+		t.Errorf("We got an unexpected result: %v games instead of %v", len(games), 1)
+	}
+	if week, _ := cache.LatestWeek("2015"); week != before+1 {
+		t.Errorf("We got an unexpected result: %v instead of %v", week, before+1)
+	}
+}
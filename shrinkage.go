@@ -0,0 +1,168 @@
+package nflwp
+
+import (
+	"math"
+)
+
+// A RatingEstimate is a team's estimated average for a metric along with its standard deviation.
+type RatingEstimate struct {
+	Mean   float64
+	StdDev float64
+	Games  float64
+}
+
+// A ShrinkagePrior describes a metric across the league.
+// TeamVariance is how much the true team averages differ from each other and
+// GameVariance is how much a single game differs from a team's true average.
+type ShrinkagePrior struct {
+	Mean         float64
+	TeamVariance float64
+	GameVariance float64
+}
+
+// Shrinkage blends a team's observed average with a prior, weighted by games played.
+// The prior for a team is the league mean, moved toward the team's average from LastSeason if there is one.
+// Regression is the fraction of the way back to the league mean that last season's average is moved,
+// so 0 keeps last season as is and 1 ignores it.
+type Shrinkage struct {
+	Priors     map[int]ShrinkagePrior
+	LastSeason AllTeamData
+	Regression float64
+}
+
+// These are rough values from looking at a few seasons of pro-football-reference data.
+// They are used when there is not enough data to fit the priors.
+var DefaultShrinkagePriors = map[int]ShrinkagePrior{
+	WPADJUST:         {Mean: 0, TeamVariance: 0.0025, GameVariance: 0.04},
+	STRAIGHTWPADJUST: {Mean: 0.5, TeamVariance: 0.01, GameVariance: 0.04},
+}
+
+// Returns a Shrinkage using DefaultShrinkagePriors.
+func NewShrinkage(LastSeason AllTeamData, Regression float64) *Shrinkage {
+	Priors := make(map[int]ShrinkagePrior)
+	for key, val := range DefaultShrinkagePriors {
+		Priors[key] = val
+	}
+	return &Shrinkage{Priors: Priors, LastSeason: LastSeason, Regression: Regression}
+}
+
+// Given the game history of a season, usually last season, fit the priors for WPADJUST and STRAIGHTWPADJUST.
+// The variances are found with the method of moments. Metrics without enough data keep the defaults.
+func FitShrinkage(History TeamHistory, LastSeason AllTeamData, Regression float64) *Shrinkage {
+	s := NewShrinkage(LastSeason, Regression)
+	for Metric := range DefaultShrinkagePriors {
+		var TeamMeans []float64
+		Sum, Games, Within := 0.0, 0.0, 0.0
+		for Team := range History {
+			Values := History.Values(Team, Metric)
+			if len(Values) == 0 {
+				continue
+			}
+			Mean := CumulativeMean{}.Aggregate(Team, Metric, Values)
+			for _, val := range Values {
+				Within += (val - Mean) * (val - Mean)
+			}
+			TeamMeans = append(TeamMeans, Mean)
+			Sum += Mean * float64(len(Values))
+			Games += float64(len(Values))
+		}
+		Teams := float64(len(TeamMeans))
+		if Teams < 2 || Games <= Teams {
+			continue
+		}
+		Prior := ShrinkagePrior{Mean: Sum / Games, GameVariance: Within / (Games - Teams)}
+		Between := 0.0
+		for _, val := range TeamMeans {
+			Between += (val - Prior.Mean) * (val - Prior.Mean)
+		}
+		// The spread of the observed team averages includes the noise of each game, so take it back out.
+		Prior.TeamVariance = math.Max(Between/(Teams-1)-Prior.GameVariance/(Games/Teams), 1e-6)
+		s.Priors[Metric] = Prior
+	}
+	return s
+}
+
+// The prior for a team before it plays any games. Last season's average is taken with AllTeamData.Average.
+func (s *Shrinkage) teamPrior(Team string, Metric int) float64 {
+	Prior := s.Priors[Metric]
+	if Average, ok := s.LastSeason.Average(Team, Metric); ok {
+		return Prior.Mean + (1-s.Regression)*(Average-Prior.Mean)
+	}
+	return Prior.Mean
+}
+
+// Given a team's observed average for a metric over some number of games, return the shrunken estimate.
+// With no games, this is the team's prior. A metric without a prior isn't shrunk, so the estimate is the
+// observed average, and its StdDev is +Inf since there is nothing to measure it against.
+func (s *Shrinkage) Estimate(Team string, Metric int, Average, Games float64) RatingEstimate {
+	Prior, ok := s.Priors[Metric]
+	if !ok {
+		return RatingEstimate{Mean: Average, StdDev: math.Inf(1), Games: Games}
+	}
+	Precision := 1 / Prior.TeamVariance
+	Mean := s.teamPrior(Team, Metric) * Precision
+	if Games > 0 {
+		Precision += Games / Prior.GameVariance
+		Mean += Games * Average / Prior.GameVariance
+	}
+	return RatingEstimate{Mean: Mean / Precision, StdDev: math.Sqrt(1 / Precision), Games: Games}
+}
+
+// Like Estimate, but the observed average comes from the season data, see AllTeamData.Average.
+// Teams not in TeamData get their prior.
+func (s *Shrinkage) EstimateTeamData(TeamData AllTeamData, Team string, Metric int) RatingEstimate {
+	if Average, ok := TeamData.Average(Team, Metric); ok {
+		Games := TeamData[Team][GAMESPLAYED]
+		if Metric == OPPWPADJUST {
+			Games--
+		}
+		return s.Estimate(Team, Metric, Average, Games)
+	}
+	return s.Estimate(Team, Metric, 0, 0)
+}
+
+// Shrinkage can be used with a SeasonBuilder. Metrics without a prior are averaged with CumulativeMean.
+func (s *Shrinkage) Aggregate(Team string, Metric int, Values []float64) float64 {
+	if _, ok := s.Priors[Metric]; !ok {
+		return CumulativeMean{}.Aggregate(Team, Metric, Values)
+	}
+	return s.Estimate(Team, Metric, CumulativeMean{}.Aggregate(Team, Metric, Values), float64(len(Values))).Mean
+}
+
+// ShrinkagePredictor is EstSpreadPredictor with the teams' WPADJUST shrunk toward their priors.
+// It doesn't need any games played, so it can be used from week 1.
+// Spreads are turned into win probabilities with the Sport's MarginStdDev, the NFL's if it is left out.
+type ShrinkagePredictor struct {
+	Shrinkage *Shrinkage
+	Sport     Sport
+}
+
+func (ShrinkagePredictor) Name() string {
+	return "Shrinkage"
+}
+
+func (ShrinkagePredictor) MinGames() float64 {
+	return 0
+}
+
+func (p ShrinkagePredictor) Predict(TeamData AllTeamData, HomeTeam, VisitingTeam string) SpreadEstimate {
+	Spread := 0.0
+	if val, ok := TeamData[HomeTeam]; ok {
+		Spread = val[SPREAD]
+	}
+	Home := p.Shrinkage.EstimateTeamData(TeamData, HomeTeam, WPADJUST)
+	Visitor := p.Shrinkage.EstimateTeamData(TeamData, VisitingTeam, WPADJUST)
+	StdDev := p.Sport.MarginStdDev
+	if StdDev == 0 {
+		StdDev = NFL.MarginStdDev
+	}
+	NewProb := WinProbability(0, Spread, StdDev) + (Home.Mean-Visitor.Mean)/2
+	Estimate := NewSpread(NewProb, Spread, StdDev)
+	return SpreadEstimate{Spread: Estimate, WinProbability: WinProbability(0, Estimate, StdDev)}
+}
+
+// Like Predict, but with the given sport in place of the predictor's Sport.
+func (p ShrinkagePredictor) PredictForSport(TeamData AllTeamData, HomeTeam, VisitingTeam string, ThisSport Sport) SpreadEstimate {
+	p.Sport = ThisSport
+	return p.Predict(TeamData, HomeTeam, VisitingTeam)
+}
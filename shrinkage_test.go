@@ -0,0 +1,89 @@
+package nflwp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShrinkageEstimate(t *testing.T) {
+	lastSeason := NewAllTeamData()
+	lastSeason["NWE"] = NewTeamData()
+	lastSeason["NWE"][GAMESPLAYED] = 16
+	lastSeason["NWE"][WPADJUST] = 16 * 0.1
+	s := &Shrinkage{Priors: map[int]ShrinkagePrior{WPADJUST: {Mean: 0, TeamVariance: 0.01, GameVariance: 0.04}}, LastSeason: lastSeason, Regression: 0.5}
+	// With no games, NWE gets last season regressed halfway to the league and everyone else gets the league mean.
+	result := s.Estimate("NWE", WPADJUST, 0, 0)
+	if math.Abs(result.Mean-0.05) > 0.0005 || math.Abs(result.StdDev-0.1) > 0.0005 {
+		t.Errorf("We got an unexpected result: %+v instead of %v +/- %v", result, 0.05, 0.1)
+	}
+	result = s.Estimate("BUF", WPADJUST, 0, 0)
+	if math.Abs(result.Mean) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", result.Mean, 0.0)
+	}
+	// Four games count as much as the prior, so the estimate is halfway between.
+	result = s.Estimate("BUF", WPADJUST, 0.2, 4)
+	if math.Abs(result.Mean-0.1) > 0.0005 || math.Abs(result.StdDev-math.Sqrt(0.005)) > 0.0005 {
+		t.Errorf("We got an unexpected result: %+v instead of %v +/- %v", result, 0.1, math.Sqrt(0.005))
+	}
+	if agg := s.Aggregate("BUF", WPADJUST, []float64{0.1, 0.3, 0.2, 0.2}); math.Abs(agg-0.1) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", agg, 0.1)
+	}
+	// Without a prior the average isn't shrunk.
+	if result = s.Estimate("NWE", OPPWPADJUST, 0.3, 4); result.Mean != 0.3 || !math.IsInf(result.StdDev, 1) {
+		t.Errorf("We got an unexpected result: %+v instead of %v", result, 0.3)
+	}
+	// Last season's OPPWPADJUST leaves out the first game, so it averaged 0.2 over 15 games.
+	lastSeason["NWE"][OPPWPADJUST] = 15 * 0.2
+	s.Priors[OPPWPADJUST] = ShrinkagePrior{Mean: 0, TeamVariance: 0.01, GameVariance: 0.04}
+	if result = s.Estimate("NWE", OPPWPADJUST, 0, 0); math.Abs(result.Mean-0.1) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", result.Mean, 0.1)
+	}
+}
+
+func TestFitShrinkage(t *testing.T) {
+	history := TeamHistory{}
+	teams := map[string][]float64{"NWE": {0.3, 0.1, 0.2}, "BUF": {-0.1, -0.3, -0.2}, "NYJ": {0.1, -0.1, 0}}
+	for team, values := range teams {
+		for _, val := range values {
+			game := NewTeamData()
+			game[GAMESPLAYED] = 1
+			game[WPADJUST] = val
+			game[STRAIGHTWPADJUST] = 0.5 + val
+			history[team] = append(history[team], game)
+		}
+	}
+	s := FitShrinkage(history, nil, 0)
+	prior := s.Priors[WPADJUST]
+	// Each team's games are 0.1 from its mean, and the team means are 0.2 from each other.
+	if math.Abs(prior.Mean) > 0.0005 || math.Abs(prior.GameVariance-0.01) > 0.0005 || math.Abs(prior.TeamVariance-(0.04-0.01/3)) > 0.0005 {
+		t.Errorf("We got an unexpected result: %+v", prior)
+	}
+	if math.Abs(s.Priors[STRAIGHTWPADJUST].Mean-0.5) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", s.Priors[STRAIGHTWPADJUST].Mean, 0.5)
+	}
+}
+
+func TestShrinkagePredictor(t *testing.T) {
+	lastSeason := NewAllTeamData()
+	lastSeason["NWE"] = NewTeamData()
+	lastSeason["NWE"][GAMESPLAYED] = 16
+	lastSeason["NWE"][WPADJUST] = 16 * 0.1
+	teamData := NewAllTeamData().ForGame(UpcomingGame{HomeTeam: "NWE", VisitingTeam: "PIT", Spread: -3})
+	// Nobody has played yet, so the prediction comes from last season alone.
+	for _, sport := range []Sport{NFL, NCAAF} {
+		predictor := ShrinkagePredictor{Shrinkage: NewShrinkage(lastSeason, 0.5), Sport: sport}
+		estimate := predictor.Predict(teamData, "NWE", "PIT")
+		if estimate.Spread >= -3 || math.Abs(estimate.WinProbability-WinProbability(0, estimate.Spread, sport.MarginStdDev)) > 1e-9 {
+			t.Errorf("We got an unexpected result for %v: %+v", sport.Name, estimate)
+		}
+	}
+	if nfl, empty := (ShrinkagePredictor{Shrinkage: NewShrinkage(lastSeason, 0.5), Sport: NFL}).Predict(teamData, "NWE", "PIT"),
+		(ShrinkagePredictor{Shrinkage: NewShrinkage(lastSeason, 0.5)}).Predict(teamData, "NWE", "PIT"); nfl != empty {
+		t.Errorf("We got an unexpected result: %+v instead of %+v", empty, nfl)
+	}
+	// PredictorEstimate's sport wins over the predictor's.
+	predictor := ShrinkagePredictor{Shrinkage: NewShrinkage(lastSeason, 0.5), Sport: NFL}
+	if result, expected := PredictorEstimate(predictor, teamData, "NWE", "PIT", NCAAF), (ShrinkagePredictor{Shrinkage: predictor.Shrinkage, Sport: NCAAF}).Predict(teamData, "NWE", "PIT"); result != expected {
+		t.Errorf("We got an unexpected result: %+v instead of %+v", result, expected)
+	}
+}
@@ -0,0 +1,427 @@
+package nflwp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A TeamRegistry maps team names to abbreviations and abbreviations to the float64 codes stored in PLAYINGTHISWEEK.
+// "BYE" is always code 0. A dynamic registry gives every team it hasn't seen the next code, which suits
+// leagues like college football with too many teams to list.
+type TeamRegistry struct {
+	mu      sync.Mutex
+	codes   map[string]float64
+	abbrs   map[float64]string
+	names   map[string]string
+	dynamic bool
+}
+
+// Make a registry with a fixed list of teams. Abbrs[i] gets code i and should start with "BYE".
+// Names maps the names the odds files use to abbreviations.
+func NewTeamRegistry(Abbrs []string, Names map[string]string) *TeamRegistry {
+	t := &TeamRegistry{codes: make(map[string]float64), abbrs: make(map[float64]string), names: Names}
+	for i, val := range Abbrs {
+		t.codes[val] = float64(i)
+		t.abbrs[float64(i)] = val
+	}
+	return t
+}
+
+// Make a registry that adds teams as it sees them.
+func NewDynamicTeamRegistry() *TeamRegistry {
+	t := NewTeamRegistry([]string{"BYE"}, nil)
+	t.dynamic = true
+	return t
+}
+
+// The code for the team. Fixed registries return 0 for teams they don't know.
+func (t *TeamRegistry) Float(Abbr string) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if val, ok := t.codes[Abbr]; ok || !t.dynamic {
+		return val
+	}
+	Code := float64(len(t.codes))
+	t.codes[Abbr] = Code
+	t.abbrs[Code] = Abbr
+	return Code
+}
+
+// The code for the team, and false if a fixed registry doesn't know it.
+func (t *TeamRegistry) Code(Abbr string) (float64, bool) {
+	Code := t.Float(Abbr)
+	return Code, Code != 0 || Abbr == "BYE"
+}
+
+// The team with the code, or "" if there isn't one.
+func (t *TeamRegistry) Abbr(Code float64) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.abbrs[Code]
+}
+
+// The abbreviation for a team name from an odds file, or "" if it isn't known.
+// Dynamic registries turn the name into a slug like "ohio-state" and add it.
+func (t *TeamRegistry) FromName(Name string) string {
+	if !t.dynamic {
+		return t.names[Name]
+	}
+	Slug := strings.Join(strings.Fields(strings.ToLower(Name)), "-")
+	if Slug != "" {
+		t.Float(Slug)
+	}
+	return Slug
+}
+
+// The teams in the registry in alphabetical order, not counting "BYE".
+func (t *TeamRegistry) Teams() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	Teams := make([]string, 0, len(t.codes))
+	for key := range t.codes {
+		if key != "BYE" {
+			Teams = append(Teams, key)
+		}
+	}
+	sort.Strings(Teams)
+	return Teams
+}
+
+// A DataSource is the site that has the box scores and win probability charts for a sport.
+// Pages are saved with CheckFileExists under FilePrefix followed by the path.
+// WeekPath is the page listing a week's games and is nil if the site doesn't have one.
+// GamePath is the box score of a game given its date as YYYYMMDD and the home team.
+type DataSource struct {
+	BaseURL    string
+	FilePrefix string
+	WeekPath   func(Year, Week string) string
+	GamePath   func(Date, HomeTeam string) string
+}
+
+// A Sport is everything about a league that the WPADJUST methodology depends on.
+// MarginStdDev is the standard deviation of the final margin around the spread.
+// A game is Periods periods of PeriodMinutes; OvertimeMinutes is how long overtime is, or 0 if it is untimed.
+// OddsName is the sport in the name of the scoresandodds files, "<Year><OddsName>OddsAndScores.txt".
+// HasBye is true if the season keeps a "BYE" team for weeks off.
+// OvertimeRule, if set, is how long overtime was in a week of a season, see ForWeek.
+type Sport struct {
+	Name            string
+	OddsName        string
+	MarginStdDev    float64
+	Periods         float64
+	PeriodMinutes   float64
+	OvertimeMinutes float64
+	HasBye          bool
+	OvertimeRule    func(Season, Week int, SeasonType string) float64
+	Teams           *TeamRegistry
+	Source          DataSource
+}
+
+// The NFL, with its data from pro-football-reference.com.
+var NFL = Sport{
+	Name:            "NFL",
+	OddsName:        "Football",
+	MarginStdDev:    STDDEV,
+	Periods:         4,
+	PeriodMinutes:   15,
+	OvertimeMinutes: 15,
+	HasBye:          true,
+	OvertimeRule:    nflOvertime,
+	Teams:           NewTeamRegistry(nflTeamAbbrs, nflTeamNames),
+	Source: DataSource{
+		BaseURL:    "http://www.pro-football-reference.com",
+		FilePrefix: "NFL",
+		WeekPath: func(Year, Week string) string {
+			return "/years/" + Year + "/week_" + Week + ".htm"
+		},
+		GamePath: func(Date, HomeTeam string) string {
+			return "/boxscores/" + Date + "0" + strings.ToLower(HomeTeam) + ".htm"
+		},
+	},
+}
+
+// College football, with its data from sports-reference.com.
+// Margins are wider than the NFL's and overtime has no clock. The site has no page for a week's games,
+// so seasons are built from the odds files rather than with SeasonBuilder.AddWeek. There are too many teams to list,
+// so teams are added as they are seen and named by the slug sports-reference uses, like "ohio-state".
+var NCAAF = Sport{
+	Name:          "NCAAF",
+	OddsName:      "CollegeFootball",
+	MarginStdDev:  16,
+	Periods:       4,
+	PeriodMinutes: 15,
+	Teams:         NewDynamicTeamRegistry(),
+	Source: DataSource{
+		BaseURL:    "https://www.sports-reference.com",
+		FilePrefix: "NCAAF",
+		GamePath: func(Date, HomeTeam string) string {
+			if len(Date) < 8 {
+				return ""
+			}
+			return "/cfb/boxscores/" + Date[:4] + "-" + Date[4:6] + "-" + Date[6:8] + "-" + HomeTeam + ".html"
+		},
+	},
+}
+
+// NFL regular season overtime has been 10 minutes since 2017, and playoff overtime is still 15.
+// Without a season type, weeks past the regular season, which went to 18 weeks in 2021, are the playoffs.
+func nflOvertime(Season, Week int, SeasonType string) float64 {
+	Regular := strings.EqualFold(SeasonType, "REG")
+	if SeasonType == "" {
+		Regular = Week <= 17 || (Season >= 2021 && Week <= 18)
+	}
+	if Regular && Season >= 2017 {
+		return 10
+	}
+	return 15
+}
+
+// The sport as it was played in a week of a season, with overtime as long as its OvertimeRule says it was.
+// SeasonType is "REG" or "POST" if it is known, and "" to go by the week.
+func (s Sport) ForWeek(Season string, Week int, SeasonType string) Sport {
+	Year, err := strconv.Atoi(Season)
+	if s.OvertimeRule == nil || err != nil {
+		return s
+	}
+	s.OvertimeMinutes = s.OvertimeRule(Year, Week, SeasonType)
+	return s
+}
+
+// Return the sport with the given name or odds file name, like "NFL" or "Football".
+func GetSport(Name string) (Sport, bool) {
+	for _, val := range []Sport{NFL, NCAAF} {
+		if strings.EqualFold(Name, val.Name) || strings.EqualFold(Name, val.OddsName) {
+			return val, true
+		}
+	}
+	return Sport{}, false
+}
+
+// How long a game is without overtime.
+func (s Sport) GameMinutes() float64 {
+	return s.Periods * s.PeriodMinutes
+}
+
+// Given the spread of a game and the info for a given play,
+// calculate the probability the spread predicts at this point of the game.
+// In an untimed overtime, or for a play without a clock, the probability stays where it was.
+func (s Sport) AdjustedStartingProbability(Spread float64, PlayInfo string, PreviousAdjustment float64) float64 {
+	Quarter, Remaining, TotalMins, ok := s.ParsePlayClock(PlayInfo)
+	if !ok || (Quarter > s.Periods && s.OvertimeMinutes == 0) {
+		return PreviousAdjustment
+	}
+	AdjustmentFactor := TotalMins / Remaining
+	return WinProbability(Spread*(1-(1/AdjustmentFactor)), Spread/AdjustmentFactor, s.MarginStdDev/math.Sqrt(AdjustmentFactor))
+}
+
+// Given the info for a play, like "\"Q3 10:00 ...\"", return the period, the minutes left in the game and how long the game is.
+// Overtime is period Periods+1 and makes the game OvertimeMinutes longer, the same way AdjustedStartingProbability counts it.
+// An untimed overtime has no clock, so its plays have no time left in a game of GameMinutes.
+func (s Sport) ParsePlayClock(PlayInfo string) (float64, float64, float64, bool) {
+	var Quarter float64
+	var err error
+	TotalMins := s.GameMinutes()
+	if len(PlayInfo) < 5 {
+		return 0, 0, 0, false
+	}
+	if PlayInfo[1] == 'O' {
+		Quarter = s.Periods + 1
+		if s.OvertimeMinutes == 0 {
+			return Quarter, 0, TotalMins, true
+		}
+		TotalMins += s.OvertimeMinutes
+	} else {
+		Quarter, err = strconv.ParseFloat(string(PlayInfo[2]), 64)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+	}
+	Clock := strings.Fields(PlayInfo[4:])
+	if len(Clock) == 0 {
+		return 0, 0, 0, false
+	}
+	Parts := strings.Split(Clock[0], ":")
+	if len(Parts) != 2 {
+		return 0, 0, 0, false
+	}
+	Minutes, err := strconv.ParseFloat(Parts[0], 64)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	Seconds, err := strconv.ParseFloat(Parts[1], 64)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	Remaining := Minutes + Seconds/60
+	if Quarter <= s.Periods {
+		Remaining += (s.Periods - Quarter) * s.PeriodMinutes
+	}
+	return Quarter, Remaining, TotalMins, true
+}
+
+// Given a quarter and clock, like "Q3 2:30" or "OT 4:00", return the minutes left in the game the way ParsePlayClock counts them.
+func (s Sport) ParseGameClock(Clock string) (float64, bool) {
+	// ParsePlayClock reads the play info from a chart, which starts with a quote.
+	_, Remaining, _, ok := s.ParsePlayClock("\"" + strings.ToUpper(strings.TrimSpace(Clock)) + " ")
+	return Remaining, ok
+}
+
+// Given the home team's lead, the spread and the minutes left in the game, return the home team's win probability.
+// The spread only counts for the part of the game that is left, and the margin's standard deviation shrinks
+// the same way, so a margin of 0 at kickoff is WinProbability(0, Spread, MarginStdDev).
+// With no time left, a lead wins, a deficit loses and a tie is a coin flip.
+func (s Sport) LiveWinProbability(Margin, Spread, Remaining float64) float64 {
+	if Remaining <= 0 {
+		switch {
+		case Margin > 0:
+			return 1
+		case Margin < 0:
+			return 0
+		}
+		return 0.5
+	}
+	Fraction := math.Min(Remaining/s.GameMinutes(), 1)
+	return WinProbability(-Margin, Spread*Fraction, s.MarginStdDev*math.Sqrt(Fraction))
+}
+
+// Fetch a page from the sport's data source, saving it to disk the same way CheckFileExists does.
+func (s Sport) fetch(Path string) []byte {
+	return CheckFileExists(s.Source.FilePrefix+strings.Replace(Path, "/", "-", -1), s.Source.BaseURL+Path)
+}
+
+// The error GetGameLinks wraps when a week's page has no games, which is how we know the season is over.
+var ErrNoGames = errors.New("there are no games")
+
+// Given a year and week number, return the box score links for the week's games.
+// Returns an error if the sport's data source has no page listing a week's games, and ErrNoGames if the page has none.
+func (s Sport) GetGameLinks(Year, Week string) ([]string, error) {
+	var Links []string
+	if s.Source.WeekPath == nil {
+		return nil, fmt.Errorf("%v has no page listing the games for a week", s.Name)
+	}
+	body := CheckFileExists(s.Source.FilePrefix+"-"+Year+"-Week"+Week, s.Source.BaseURL+s.Source.WeekPath(Year, Week))
+	GameURLs := FindAllBetween(body, "gamelink[^h]*href=\"", "\">")
+	for _, val := range GameURLs {
+		ThisGameLink := FindAllBetween([]byte(val), "/boxscores", ".htm")
+		if ThisGameLink == nil {
+			fmt.Println("Cannot find a game link in", val)
+			continue
+		}
+		Links = append(Links, ThisGameLink[0])
+	}
+	if Links == nil {
+		return nil, fmt.Errorf("%w for week %v of %v", ErrNoGames, Week, Year)
+	}
+	return Links, nil
+}
+
+// Given a box score link, find the GameResult for the game with the adjustments weighted with the given method.
+// The page needs a chartData win probability chart like pro-football-reference's.
+// Overtime is as long as the sport's OvertimeMinutes; use GetWeekGameResult when the game's week is known.
+// If we incure an error, nil is returned.
+func (s Sport) GetGameResult(Link string, Method AdjustmentMethod) *GameResult {
+	body := s.fetch(Link)
+	VisitingTeam, HomeTeam := GetTeamNames(string(body))
+	Points := ParseChartDataForSport(body, s)
+	if VisitingTeam == "" || Points == nil {
+		fmt.Println("We didn't find the data we need on the provided page so we can't return anything")
+		return nil
+	}
+	Result := &GameResult{
+		Link:         Link,
+		Date:         GetDateFromGameLink(Link),
+		HomeTeam:     HomeTeam,
+		VisitingTeam: VisitingTeam,
+		Spread:       GetSpreadFromProFootballPage(body, VisitingTeam, HomeTeam),
+		PregameWP:    Points[0].HomeWP,
+		FinalWP:      Points[len(Points)-1].HomeWP,
+		Points:       Points,
+	}
+	Result.VisitingScore, Result.HomeScore, _ = GetFinalScoreFromProFootballPage(body, VisitingTeam, HomeTeam)
+	Result.SetAdjustmentsForSport(Points, Method, s)
+	return Result
+}
+
+// Like GetGameResult, but with the overtime of the given week, see ForWeek, and the game's Season and Week set.
+func (s Sport) GetWeekGameResult(Link, Season string, Week int, Method AdjustmentMethod) *GameResult {
+	Result := s.ForWeek(Season, Week, "").GetGameResult(Link, Method)
+	if Result != nil {
+		Result.Season = Season
+		Result.Week = Week
+	}
+	return Result
+}
+
+// The box score link for a game from an odds file.
+func (s Sport) GameLink(Record OddsRecord) string {
+	return s.Source.GamePath(Record.Date, s.Teams.FromName(Record.HomeName))
+}
+
+// Given the day of the first game of the season, return the week of a game played on Date, counting seven day blocks from 1.
+func SeasonWeek(FirstDate, Date time.Time) int {
+	return int(Date.Sub(FirstDate).Hours()/24)/7 + 1
+}
+
+// A SpreadGame is a game from an odds file along with its box score.
+type SpreadGame struct {
+	GameResult
+	Total float64
+}
+
+// Given a year, read the sport's odds file "<Year><OddsName>OddsAndScores.txt" and look up each game's box score.
+// The GameResult has the registry's names for the teams and the odds file's date, spread and scores,
+// and its week is counted in seven day blocks from the first game in the file.
+// Games that can't be parsed, that have a team the registry doesn't know or whose box score can't be read are printed and skipped.
+func (s Sport) LoadGamesFromSpreadFile(Year int) ([]SpreadGame, error) {
+	var Games []SpreadGame
+	var FirstDate time.Time
+	file, err := os.Open(strconv.Itoa(Year) + s.OddsName + "OddsAndScores.txt")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	Odds := NewOddsReader(file)
+	for {
+		Record, err := Odds.Read()
+		if err == io.EOF {
+			break
+		}
+		if _, ok := err.(*OddsParseError); ok {
+			fmt.Println("Error: ", err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		HomeTeam, VisitingTeam := s.Teams.FromName(Record.HomeName), s.Teams.FromName(Record.VisitingName)
+		if HomeTeam == "" || VisitingTeam == "" {
+			fmt.Printf("Error: skipping %v at %v on line %v since we don't know one of the teams\n", Record.VisitingName, Record.HomeName, Record.Line)
+			continue
+		}
+		Date, _ := time.Parse("20060102", Record.Date)
+		if FirstDate.IsZero() {
+			FirstDate = Date
+		}
+		Result := s.GetWeekGameResult(s.GameLink(Record), strconv.Itoa(Year), SeasonWeek(FirstDate, Date), PERPLAY)
+		if Result == nil {
+			fmt.Println("Error getting game data for link", s.GameLink(Record))
+			continue
+		}
+		// The box score may name the teams differently than the odds file, so use the registry's names for both.
+		Result.HomeTeam, Result.VisitingTeam = HomeTeam, VisitingTeam
+		Result.Date = Record.Date
+		Result.Spread = Record.Spread
+		Result.HomeScore = Record.HomeScore
+		Result.VisitingScore = Record.VisitingScore
+		Games = append(Games, SpreadGame{GameResult: *Result, Total: Record.Total})
+	}
+	return Games, nil
+}
@@ -0,0 +1,180 @@
+package nflwp
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTeamRegistry(t *testing.T) {
+	if NFL.Teams.Float("GNB") != 15 || NFL.Teams.Abbr(15) != "GNB" || NFL.Teams.FromName("PACKERS") != "GNB" {
+		t.Errorf("We got an unexpected result: %v %v %v", NFL.Teams.Float("GNB"), NFL.Teams.Abbr(15), NFL.Teams.FromName("PACKERS"))
+	}
+	if NFL.Teams.Float("XXX") != 0 || len(NFL.Teams.Teams()) != 32 {
+		t.Errorf("We got an unexpected result: %v and %v teams", NFL.Teams.Float("XXX"), len(NFL.Teams.Teams()))
+	}
+	teams := NewDynamicTeamRegistry()
+	slug := teams.FromName("OHIO STATE")
+	if slug != "ohio-state" || teams.Float(slug) != 1 || teams.Float("alabama") != 2 || teams.Abbr(2) != "alabama" || teams.Float("BYE") != 0 {
+		t.Errorf("We got an unexpected result: %v %v %v", slug, teams.Float(slug), teams.Float("alabama"))
+	}
+}
+
+func TestAdjustedStartingProbability(t *testing.T) {
+	spreads := []float64{-7, 3, -5, 0, 10}
+	infos := []string{"\"Q1 5:00 GNB 0-CHI 0 32.20%\"", "\"Q2 12:00 GNB 0-CHI 0 32.20%\"",
+		"\"Q3 10:00 GNB 0-CHI 0 32.20%\"", "\"OT 2:00 GNB 0-CHI 0 32.20%\"", "\"Q3 2:00 GNB 0-CHI 0 32.20%\""}
+	expectedResults := []float64{0.7155416690111231, 0.39499227195906944, 0.7173388898503317, 0.5, 0.08175156445183962}
+	for i := 0; i < len(spreads); i++ {
+		result := NFL.AdjustedStartingProbability(spreads[i], infos[i], 9.0)
+		if math.Abs(result-expectedResults[i]) > 1e-9 || result != FindAdjustedStartingProbability(spreads[i], infos[i], 9.0) {
+			t.Errorf("We got an unexpected result: %v instead of %v", result, expectedResults[i])
+		}
+	}
+	// Clocks we can't read keep the adjustment we had.
+	for _, info := range []string{"\"Q3 GNB 0-CHI 0\"", "\"Q3 10:\"", "\"Q3 :", "null"} {
+		if result := NFL.AdjustedStartingProbability(-7, info, 0.42); result != 0.42 {
+			t.Errorf("We got an unexpected result for %v: %v instead of %v", info, result, 0.42)
+		}
+	}
+	// College margins are wider, so the same spread means less.
+	if nfl, ncaaf := NFL.AdjustedStartingProbability(-7, infos[0], 0), NCAAF.AdjustedStartingProbability(-7, infos[0], 0); ncaaf >= nfl || ncaaf <= 0.5 {
+		t.Errorf("We got an unexpected result: %v for NCAAF and %v for the NFL", ncaaf, nfl)
+	}
+	if result := NCAAF.AdjustedStartingProbability(-7, "\"OT 0:00 OSU 0-MICH 0 50.00%\"", 0.42); result != 0.42 {
+		t.Errorf("We got an unexpected result: %v instead of %v", result, 0.42)
+	}
+}
+
+func TestSportGameLinks(t *testing.T) {
+	record := OddsRecord{Date: "20150905", HomeName: "OHIO STATE"}
+	if link := NCAAF.GameLink(record); link != "/cfb/boxscores/2015-09-05-ohio-state.html" {
+		t.Errorf("We got an unexpected result: %v", link)
+	}
+	if date := GetDateFromGameLink("/cfb/boxscores/2015-09-05-ohio-state.html"); date != "20150905" {
+		t.Errorf("We got an unexpected result: %v instead of %v", date, "20150905")
+	}
+	record = OddsRecord{Date: "20150910", HomeName: "PATRIOTS", HomeTeam: "NWE"}
+	if link := NFL.GameLink(record); link != record.GameLink() || link != "/boxscores/201509100nwe.htm" {
+		t.Errorf("We got an unexpected result: %v", link)
+	}
+	for _, name := range []string{"Football", "nfl", "CollegeFootball", "NCAAF"} {
+		if _, ok := GetSport(name); !ok {
+			t.Errorf("We expected to find the sport %v", name)
+		}
+	}
+	if _, ok := GetSport("Basketball"); ok {
+		t.Errorf("We didn't expect to find Basketball")
+	}
+}
+
+func TestEmptyWeekPages(t *testing.T) {
+	sport := NFL
+	sport.Source.FilePrefix = filepath.Join(t.TempDir(), "NFL")
+	if err := os.WriteFile(sport.Source.FilePrefix+"-2015-Week1", []byte("<html><body>No games this week</body></html>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if links, err := sport.GetGameLinks("2015", "1"); !errors.Is(err, ErrNoGames) || links != nil {
+		t.Errorf("We got an unexpected result: %v %v", links, err)
+	}
+	builder := NewSeasonBuilderForSport(nil, sport)
+	if err := builder.AddYear("2015", -1); !errors.Is(err, ErrNoGames) {
+		t.Errorf("We got an unexpected result: %v instead of %v", err, ErrNoGames)
+	}
+	if err := builder.AddWeek("2015", "one"); err == nil {
+		t.Errorf("We should not be able to add the week \"one\"")
+	}
+	if visitor, home := GetTeamNames("<html><body>No chart here</body></html>"); visitor != "" || home != "" {
+		t.Errorf("We got an unexpected result: %q and %q", visitor, home)
+	}
+}
+
+func TestSeasonBuilderForSport(t *testing.T) {
+	builder := NewSeasonBuilderForSport(nil, NCAAF)
+	if _, ok := builder.TeamData["BYE"]; ok {
+		t.Errorf("We didn't expect a BYE team for NCAAF")
+	}
+	result := GameResult{HomeTeam: "ohio-state", VisitingTeam: "michigan", HomeWPADJUST: 0.1, VisitingWPADJUST: -0.1, FinalWP: 1}
+	builder.AddGameResult(result)
+	if opp := NCAAF.Teams.Abbr(builder.History["ohio-state"][0][PLAYINGTHISWEEK]); opp != "michigan" {
+		t.Errorf("We got an unexpected result: %v instead of %v", opp, "michigan")
+	}
+	if math.Abs(builder.TeamData["ohio-state"][WPADJUST]-0.1) > 1e-9 {
+		t.Errorf("We got an unexpected result: %v instead of %v", builder.TeamData["ohio-state"][WPADJUST], 0.1)
+	}
+	// The opponents round trip and solve with the sport's registry, not the NFL's.
+	ratings, converged := builder.TeamData.SolveOpponentAdjustmentForSport(builder.History, 1e-9, 1000, NCAAF)
+	if !converged || math.Abs(ratings["ohio-state"]-ratings["michigan"]-0.1) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v", ratings)
+	}
+	teamData := NewAllTeamData().AddUpcomingGamesForSport([]UpcomingGame{{HomeTeam: "ohio-state", VisitingTeam: "michigan", Spread: -7}}, NCAAF)
+	data, err := teamData.MarshalJSONForSport(NCAAF)
+	if err != nil || !strings.Contains(string(data), `"PLAYINGTHISWEEK":"michigan"`) {
+		t.Errorf("We got an unexpected result: %s %v", data, err)
+	}
+	var decoded AllTeamData
+	if err = decoded.UnmarshalJSONForSport(data, NCAAF); err != nil || decoded["ohio-state"][PLAYINGTHISWEEK] != teamData["ohio-state"][PLAYINGTHISWEEK] {
+		t.Errorf("We got an unexpected result: %v %v", decoded, err)
+	}
+	var buf bytes.Buffer
+	if err = teamData.WriteCSVForSport(&buf, NCAAF); err != nil || !strings.Contains(buf.String(), "ohio-state") {
+		t.Errorf("We got an unexpected result: %v %v", buf.String(), err)
+	}
+	if decoded, err = ReadAllTeamDataCSVForSport(&buf, NCAAF); err != nil || decoded["michigan"][PLAYINGTHISWEEK] != teamData["michigan"][PLAYINGTHISWEEK] {
+		t.Errorf("We got an unexpected result: %v %v", decoded, err)
+	}
+	// sports-reference has no page for a week's games.
+	if links, err := NCAAF.GetGameLinks("2015", "1"); err == nil || links != nil {
+		t.Errorf("We got an unexpected result: %v %v", links, err)
+	}
+	if err = builder.AddYear("2015", 3); err == nil {
+		t.Errorf("We should not be able to add a college football week")
+	}
+}
+
+func TestSportForWeek(t *testing.T) {
+	seasons := []string{"2015", "2019", "2019", "2019", "2021", "2021"}
+	weeks := []int{1, 1, 18, 18, 18, 18}
+	types := []string{"REG", "REG", "POST", "", "", ""}
+	sports := []Sport{NFL, NFL, NFL, NFL, NFL, NCAAF}
+	expectedResults := []float64{15, 10, 15, 15, 10, 0}
+	for i := range seasons {
+		if result := sports[i].ForWeek(seasons[i], weeks[i], types[i]).OvertimeMinutes; result != expectedResults[i] {
+			t.Errorf("We got an unexpected result for %v week %v: %v instead of %v", seasons[i], weeks[i], result, expectedResults[i])
+		}
+	}
+}
+
+func TestSeasonWeek(t *testing.T) {
+	first := time.Date(2015, 9, 10, 0, 0, 0, 0, time.UTC)
+	dates := []time.Time{first, first.AddDate(0, 0, 4), first.AddDate(0, 0, 7), first.AddDate(0, 0, 17)}
+	expectedResults := []int{1, 1, 2, 3}
+	for i := range dates {
+		if result := SeasonWeek(first, dates[i]); result != expectedResults[i] {
+			t.Errorf("We got an unexpected result: %v instead of %v", result, expectedResults[i])
+		}
+	}
+}
+
+func TestLiveWinProbability(t *testing.T) {
+	margins := []float64{0, 0, 7, -3, 3, 0, -1}
+	spreads := []float64{-7, 3, -7, -7, 0, -3, 10}
+	remaining := []float64{60, 60, 30, 0, 0, 0, 0}
+	expectedResults := []float64{WinProbability(0, -7, STDDEV), WinProbability(0, 3, STDDEV), WinProbability(-7, -3.5, STDDEV/math.Sqrt(2)), 0, 1, 0.5, 0}
+	for i := 0; i < len(margins); i++ {
+		result := LiveWinProbability(margins[i], spreads[i], remaining[i])
+		if math.Abs(result-expectedResults[i]) > 1e-9 {
+			t.Errorf("We got an unexpected result: %v instead of %v", result, expectedResults[i])
+		}
+	}
+	// A spread's worth of lead halfway through is worth the same as the spread decaying on its own.
+	info := "\"Q3 15:00 GNB 0-CHI 0 32.20%\""
+	if result, expected := LiveWinProbability(3.5, -7, 30), FindAdjustedStartingProbability(-7, info, 0); math.Abs(result-expected) > 1e-9 {
+		t.Errorf("We got an unexpected result: %v instead of %v", result, expected)
+	}
+}
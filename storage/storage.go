@@ -0,0 +1,412 @@
+// Package storage keeps season data in a SQLite database so it can be queried
+// without scraping and parsing everything again.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thedadams/nflwp"
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS teams (
+	code TEXT PRIMARY KEY
+);
+CREATE TABLE IF NOT EXISTS games (
+	link TEXT PRIMARY KEY,
+	date TEXT NOT NULL,
+	season TEXT NOT NULL,
+	week INTEGER NOT NULL,
+	home_team TEXT NOT NULL REFERENCES teams(code),
+	visiting_team TEXT NOT NULL REFERENCES teams(code),
+	spread REAL NOT NULL,
+	home_score REAL NOT NULL,
+	visiting_score REAL NOT NULL,
+	pregame_wp REAL NOT NULL,
+	final_wp REAL NOT NULL,
+	home_wpadjust REAL NOT NULL,
+	visiting_wpadjust REAL NOT NULL,
+	home_straightwpadjust REAL NOT NULL,
+	visiting_straightwpadjust REAL NOT NULL,
+	plays INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS games_season_week ON games (season, week);
+CREATE TABLE IF NOT EXISTS lines (
+	game_link TEXT NOT NULL,
+	source TEXT NOT NULL,
+	recorded_at TEXT NOT NULL,
+	spread REAL NOT NULL,
+	total REAL NOT NULL,
+	home_moneyline REAL NOT NULL,
+	visiting_moneyline REAL NOT NULL,
+	PRIMARY KEY (game_link, source, recorded_at)
+);
+CREATE TABLE IF NOT EXISTS wp_points (
+	game_link TEXT NOT NULL REFERENCES games(link),
+	idx INTEGER NOT NULL,
+	home_wp REAL NOT NULL,
+	play_info TEXT NOT NULL,
+	quarter REAL NOT NULL,
+	elapsed REAL NOT NULL,
+	remaining REAL NOT NULL,
+	total_minutes REAL NOT NULL,
+	PRIMARY KEY (game_link, idx)
+);
+CREATE TABLE IF NOT EXISTS team_weeks (
+	season TEXT NOT NULL,
+	week INTEGER NOT NULL,
+	team TEXT NOT NULL REFERENCES teams(code),
+	wpadjust REAL NOT NULL,
+	straightwpadjust REAL NOT NULL,
+	gamesplayed REAL NOT NULL,
+	gameswon REAL NOT NULL,
+	oppwpadjust REAL NOT NULL,
+	spread REAL NOT NULL,
+	playingthisweek REAL NOT NULL,
+	PRIMARY KEY (season, week, team)
+);
+`
+
+// A Store is a SQLite database of season data.
+type Store struct {
+	db *sql.DB
+}
+
+// Open the SQLite database at the given path, creating the tables if they don't exist.
+// Use ":memory:" for a database that goes away when it is closed.
+func Open(Path string) (*Store, error) {
+	db, err := sql.Open("sqlite", Path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite only allows one writer, and every connection to ":memory:" is a different database.
+	// With the one connection, turning on foreign keys once holds for every query.
+	db.SetMaxOpenConns(1)
+	if _, err = db.Exec(`PRAGMA foreign_keys = ON`); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err = db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// The underlying database, for queries the Store doesn't have a method for.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+func saveTeam(tx *sql.Tx, Team string) error {
+	_, err := tx.Exec(`INSERT INTO teams (code) VALUES (?) ON CONFLICT (code) DO NOTHING`, Team)
+	return err
+}
+
+// Save the season data as it stood after the given week. Saving the same week again replaces it.
+func (s *Store) SaveTeamData(Season string, Week int, TeamData nflwp.AllTeamData) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err = saveTeamData(tx, Season, Week, TeamData); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveTeamData(tx *sql.Tx, Season string, Week int, TeamData nflwp.AllTeamData) error {
+	for Team, val := range TeamData {
+		if err := saveTeam(tx, Team); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`INSERT INTO team_weeks (season, week, team, wpadjust, straightwpadjust, gamesplayed, gameswon, oppwpadjust, spread, playingthisweek)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (season, week, team) DO UPDATE SET
+				wpadjust = excluded.wpadjust,
+				straightwpadjust = excluded.straightwpadjust,
+				gamesplayed = excluded.gamesplayed,
+				gameswon = excluded.gameswon,
+				oppwpadjust = excluded.oppwpadjust,
+				spread = excluded.spread,
+				playingthisweek = excluded.playingthisweek`,
+			Season, Week, Team, val[nflwp.WPADJUST], val[nflwp.STRAIGHTWPADJUST], val[nflwp.GAMESPLAYED], val[nflwp.GAMESWON],
+			val[nflwp.OPPWPADJUST], val[nflwp.SPREAD], val[nflwp.PLAYINGTHISWEEK])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load the season data as it stood after the given week.
+// The AllTeamData is empty if the week hasn't been saved.
+func (s *Store) LoadTeamData(Season string, Week int) (nflwp.AllTeamData, error) {
+	rows, err := s.db.Query(`SELECT team, wpadjust, straightwpadjust, gamesplayed, gameswon, oppwpadjust, spread, playingthisweek
+		FROM team_weeks WHERE season = ? AND week = ?`, Season, Week)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	TeamData := nflwp.NewAllTeamData()
+	for rows.Next() {
+		var Team string
+		val := nflwp.NewTeamData()
+		err = rows.Scan(&Team, &val[nflwp.WPADJUST], &val[nflwp.STRAIGHTWPADJUST], &val[nflwp.GAMESPLAYED], &val[nflwp.GAMESWON],
+			&val[nflwp.OPPWPADJUST], &val[nflwp.SPREAD], &val[nflwp.PLAYINGTHISWEEK])
+		if err != nil {
+			return nil, err
+		}
+		TeamData[Team] = val
+	}
+	return TeamData, rows.Err()
+}
+
+// The last week saved for the season, or 0 if there are none.
+func (s *Store) LatestWeek(Season string) (int, error) {
+	var Week sql.NullInt64
+	err := s.db.QueryRow(`SELECT MAX(week) FROM team_weeks WHERE season = ?`, Season).Scan(&Week)
+	return int(Week.Int64), err
+}
+
+// Load every week saved for the season, oldest first.
+func (s *Store) LoadTimeline(Season string) (nflwp.SeasonTimeline, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT week FROM team_weeks WHERE season = ? ORDER BY week`, Season)
+	if err != nil {
+		return nil, err
+	}
+	var Weeks []int
+	for rows.Next() {
+		var Week int
+		if err = rows.Scan(&Week); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		Weeks = append(Weeks, Week)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	var Timeline nflwp.SeasonTimeline
+	for _, Week := range Weeks {
+		TeamData, err := s.LoadTeamData(Season, Week)
+		if err != nil {
+			return nil, err
+		}
+		Timeline = append(Timeline, nflwp.WeekSnapshot{Week: Week, TeamData: TeamData})
+	}
+	return Timeline, nil
+}
+
+// Save a game along with its chart. Saving the same game again replaces it.
+func (s *Store) SaveGame(Result nflwp.GameResult) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err = saveGame(tx, Result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveGame(tx *sql.Tx, Result nflwp.GameResult) error {
+	for _, Team := range []string{Result.HomeTeam, Result.VisitingTeam} {
+		if err := saveTeam(tx, Team); err != nil {
+			return err
+		}
+	}
+	_, err := tx.Exec(`INSERT INTO games (link, date, season, week, home_team, visiting_team, spread, home_score, visiting_score,
+			pregame_wp, final_wp, home_wpadjust, visiting_wpadjust, home_straightwpadjust, visiting_straightwpadjust, plays)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (link) DO UPDATE SET
+			date = excluded.date,
+			season = excluded.season,
+			week = excluded.week,
+			home_team = excluded.home_team,
+			visiting_team = excluded.visiting_team,
+			spread = excluded.spread,
+			home_score = excluded.home_score,
+			visiting_score = excluded.visiting_score,
+			pregame_wp = excluded.pregame_wp,
+			final_wp = excluded.final_wp,
+			home_wpadjust = excluded.home_wpadjust,
+			visiting_wpadjust = excluded.visiting_wpadjust,
+			home_straightwpadjust = excluded.home_straightwpadjust,
+			visiting_straightwpadjust = excluded.visiting_straightwpadjust,
+			plays = excluded.plays`,
+		Result.Link, Result.Date, Result.Season, Result.Week, Result.HomeTeam, Result.VisitingTeam, Result.Spread,
+		Result.HomeScore, Result.VisitingScore, Result.PregameWP, Result.FinalWP, Result.HomeWPADJUST, Result.VisitingWPADJUST,
+		Result.HomeSTRAIGHTWPADJUST, Result.VisitingSTRAIGHTWPADJUST, Result.Plays)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`DELETE FROM wp_points WHERE game_link = ?`, Result.Link); err != nil {
+		return err
+	}
+	for i, val := range Result.Points {
+		_, err = tx.Exec(`INSERT INTO wp_points (game_link, idx, home_wp, play_info, quarter, elapsed, remaining, total_minutes)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, Result.Link, i, val.HomeWP, val.PlayInfo, val.Quarter, val.Elapsed, val.Remaining, val.TotalMinutes)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const gameColumns = `link, date, season, week, home_team, visiting_team, spread, home_score, visiting_score,
+	pregame_wp, final_wp, home_wpadjust, visiting_wpadjust, home_straightwpadjust, visiting_straightwpadjust, plays`
+
+func scanGame(rows interface{ Scan(...interface{}) error }) (nflwp.GameResult, error) {
+	var Result nflwp.GameResult
+	err := rows.Scan(&Result.Link, &Result.Date, &Result.Season, &Result.Week, &Result.HomeTeam, &Result.VisitingTeam,
+		&Result.Spread, &Result.HomeScore, &Result.VisitingScore, &Result.PregameWP, &Result.FinalWP, &Result.HomeWPADJUST,
+		&Result.VisitingWPADJUST, &Result.HomeSTRAIGHTWPADJUST, &Result.VisitingSTRAIGHTWPADJUST, &Result.Plays)
+	return Result, err
+}
+
+// Load a single game along with its chart. Returns sql.ErrNoRows if the game hasn't been saved.
+func (s *Store) LoadGame(Link string) (*nflwp.GameResult, error) {
+	Result, err := scanGame(s.db.QueryRow(`SELECT `+gameColumns+` FROM games WHERE link = ?`, Link))
+	if err != nil {
+		return nil, err
+	}
+	Result.Points, err = s.LoadPoints(Link)
+	if err != nil {
+		return nil, err
+	}
+	return &Result, nil
+}
+
+// Load the chart of a game.
+func (s *Store) LoadPoints(Link string) ([]nflwp.WPPoint, error) {
+	rows, err := s.db.Query(`SELECT home_wp, play_info, quarter, elapsed, remaining, total_minutes
+		FROM wp_points WHERE game_link = ? ORDER BY idx`, Link)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var Points []nflwp.WPPoint
+	for rows.Next() {
+		var val nflwp.WPPoint
+		if err = rows.Scan(&val.HomeWP, &val.PlayInfo, &val.Quarter, &val.Elapsed, &val.Remaining, &val.TotalMinutes); err != nil {
+			return nil, err
+		}
+		Points = append(Points, val)
+	}
+	return Points, rows.Err()
+}
+
+// Load every game of the season in the order they were played, without their charts.
+// If Week > 0, only that week's games are loaded.
+func (s *Store) LoadGames(Season string, Week int) ([]nflwp.GameResult, error) {
+	Query := `SELECT ` + gameColumns + ` FROM games WHERE season = ?`
+	Args := []interface{}{Season}
+	if Week > 0 {
+		Query += ` AND week = ?`
+		Args = append(Args, Week)
+	}
+	rows, err := s.db.Query(Query+` ORDER BY week, date, link`, Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var Games []nflwp.GameResult
+	for rows.Next() {
+		Result, err := scanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		Games = append(Games, Result)
+	}
+	return Games, rows.Err()
+}
+
+// Save everything a SeasonBuilder has gathered: each game and the season data after each week, see SeasonBuilder.Timeline.
+// The last week is saved as the Builder's TeamData, so spreads added since the last game are kept.
+// A Builder without games has no weeks, so nothing is saved.
+// The season is saved all at once, and it is an error, with nothing saved, if a game is from another season.
+func (s *Store) SaveSeason(Season string, Builder *nflwp.SeasonBuilder) error {
+	for _, val := range Builder.Games {
+		if val.Season != Season {
+			return fmt.Errorf("the game %v is from the %v season, not %v", val.Link, val.Season, Season)
+		}
+	}
+	Timeline := Builder.Timeline()
+	if len(Timeline) == 0 {
+		return nil
+	}
+	Timeline[len(Timeline)-1].TeamData = Builder.TeamData
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, val := range Builder.Games {
+		if err = saveGame(tx, val); err != nil {
+			return err
+		}
+	}
+	for _, val := range Timeline {
+		if err = saveTeamData(tx, Season, val.Week, val.TeamData); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Line times are stored in UTC with every digit of the nanoseconds, so they sort as text.
+const timeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// Save a quote for a game, moneylines included.
+func (s *Store) SaveLineQuote(Link string, Quote nflwp.LineQuote) error {
+	_, err := s.db.Exec(`INSERT INTO lines (game_link, source, recorded_at, spread, total, home_moneyline, visiting_moneyline) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (game_link, source, recorded_at) DO UPDATE SET spread = excluded.spread, total = excluded.total,
+			home_moneyline = excluded.home_moneyline, visiting_moneyline = excluded.visiting_moneyline`,
+		Link, Quote.Source, Quote.RecordedAt.UTC().Format(timeLayout), Quote.Spread, Quote.Total, Quote.HomeMoneyline, Quote.VisitingMoneyline)
+	return err
+}
+
+// Load the quotes for the given games, or for every game if no links are given.
+// Lines with a time that isn't RFC 3339 are skipped.
+func (s *Store) LoadLineHistory(Links ...string) (nflwp.LineHistory, error) {
+	Query := `SELECT game_link, source, recorded_at, spread, total, home_moneyline, visiting_moneyline FROM lines`
+	Args := make([]interface{}, len(Links))
+	if len(Links) > 0 {
+		Query += ` WHERE game_link IN (?` + strings.Repeat(`, ?`, len(Links)-1) + `)`
+		for i, val := range Links {
+			Args[i] = val
+		}
+	}
+	rows, err := s.db.Query(Query+` ORDER BY game_link, recorded_at`, Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	History := make(nflwp.LineHistory)
+	for rows.Next() {
+		var Link, RecordedAt string
+		var Quote nflwp.LineQuote
+		if err = rows.Scan(&Link, &Quote.Source, &RecordedAt, &Quote.Spread, &Quote.Total, &Quote.HomeMoneyline, &Quote.VisitingMoneyline); err != nil {
+			return nil, err
+		}
+		if Quote.RecordedAt, err = time.Parse(time.RFC3339Nano, RecordedAt); err != nil {
+			fmt.Println("Error: ", "skipping the line for", Link, "from", Quote.Source, err)
+			continue
+		}
+		History.Add(Link, Quote)
+	}
+	return History, rows.Err()
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thedadams/nflwp"
+)
+
+func TestTeamDataRoundTrip(t *testing.T) {
+	s, err := Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	teamData := nflwp.NewAllTeamData()
+	teamData["NWE"] = nflwp.NewTeamData()
+	teamData["NWE"][nflwp.WPADJUST] = 0.4
+	teamData["NWE"][nflwp.GAMESPLAYED] = 3
+	teamData["NWE"][nflwp.PLAYINGTHISWEEK] = nflwp.GetTeamFloatFromAbbr("BUF")
+	// Saving twice is an update, not a second row.
+	for i := 0; i < 2; i++ {
+		if err = s.SaveTeamData("2015", 3, teamData); err != nil {
+			t.Fatal(err)
+		}
+		teamData["NWE"][nflwp.GAMESWON] = 2
+	}
+	loaded, err := s.LoadTeamData("2015", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < nflwp.TOTALDATAPOINTS; i++ {
+		if loaded["NWE"][i] != teamData["NWE"][i] {
+			t.Errorf("We got an unexpected result for %v: %v instead of %v", i, loaded["NWE"][i], teamData["NWE"][i])
+		}
+	}
+	if week, err := s.LatestWeek("2015"); err != nil || week != 3 {
+		t.Errorf("We got an unexpected result: %v %v instead of %v", week, err, 3)
+	}
+	if week, err := s.LatestWeek("2016"); err != nil || week != 0 {
+		t.Errorf("We got an unexpected result: %v %v instead of %v", week, err, 0)
+	}
+}
+
+func TestGameRoundTrip(t *testing.T) {
+	s, err := Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	game := nflwp.GameResult{
+		Link: "/boxscores/201509100nwe.htm", Date: "20150910", Season: "2015", Week: 1, HomeTeam: "NWE", VisitingTeam: "PIT",
+		Spread: -7, HomeScore: 28, VisitingScore: 21, PregameWP: 0.7, FinalWP: 1, HomeWPADJUST: 0.05, VisitingWPADJUST: -0.05, Plays: 2,
+		Points: []nflwp.WPPoint{{HomeWP: 0.7, PlayInfo: "\"Q1 15:00\"", Quarter: 1, Remaining: 60, TotalMinutes: 60}, {HomeWP: 1, PlayInfo: "\"Q4 0:00\"", Quarter: 4, Elapsed: 60, TotalMinutes: 60}},
+	}
+	if err = s.SaveGame(game); err != nil {
+		t.Fatal(err)
+	}
+	game.HomeScore = 31
+	game.Points = game.Points[1:]
+	if err = s.SaveGame(game); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := s.LoadGame(game.Link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.HomeScore != 31 || loaded.Spread != -7 || len(loaded.Points) != 1 || loaded.Points[0] != game.Points[0] {
+		t.Errorf("We got an unexpected result: %+v", loaded)
+	}
+	games, err := s.LoadGames("2015", 0)
+	if err != nil || len(games) != 1 || games[0].VisitingTeam != "PIT" {
+		t.Errorf("We got an unexpected result: %+v %v", games, err)
+	}
+	if games, _ = s.LoadGames("2015", 2); len(games) != 0 {
+		t.Errorf("We got an unexpected result: %+v", games)
+	}
+}
+
+func TestLineHistoryRoundTrip(t *testing.T) {
+	path := t.TempDir() + "/lines.db"
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// With RFC 3339, "00Z" sorts after "00.5Z" even though it is earlier.
+	old := nflwp.LineQuote{Source: "old", RecordedAt: time.Date(2015, 9, 1, 0, 0, 0, 0, time.UTC), Spread: -4, Total: 50}
+	if err = s.SaveLineQuote("/boxscores/201509100nwe.htm", old); err != nil {
+		t.Fatal(err)
+	}
+	old.RecordedAt, old.Spread = old.RecordedAt.Add(500*time.Millisecond), -5
+	if err = s.SaveLineQuote("/boxscores/201509100nwe.htm", old); err != nil {
+		t.Fatal(err)
+	}
+	var first float64
+	if err = s.DB().QueryRow(`SELECT spread FROM lines ORDER BY recorded_at LIMIT 1`).Scan(&first); err != nil || first != -4 {
+		t.Errorf("We got an unexpected result: %v %v instead of %v", first, err, -4)
+	}
+	// A line with a bad time is skipped rather than failing the load.
+	if _, err = s.DB().Exec(`INSERT INTO lines VALUES ('/boxscores/201509130buf.htm', 'bad', 'yesterday', -3, 40, 0, 0)`); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	if s, err = Open(path); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	recorded := time.Date(2015, 9, 10, 12, 0, 0, 0, time.FixedZone("EDT", -4*3600))
+	quote := nflwp.LineQuote{Source: "new", RecordedAt: recorded, Spread: -7, Total: 51, HomeMoneyline: -300, VisitingMoneyline: 250}
+	if err = s.SaveLineQuote("/boxscores/201509100nwe.htm", quote); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.SaveLineQuote("/boxscores/201509130buf.htm", quote); err != nil {
+		t.Fatal(err)
+	}
+	history, err := s.LoadLineHistory("/boxscores/201509100nwe.htm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || len(history["/boxscores/201509100nwe.htm"]) != 3 {
+		t.Errorf("We got an unexpected result: %v instead of %v quotes", history, 3)
+	}
+	opening, _ := history.Opening("/boxscores/201509100nwe.htm", "")
+	closing, _ := history.Closing("/boxscores/201509100nwe.htm", "", time.Time{})
+	if opening.Source != "old" || opening.Spread != -4 || closing.HomeMoneyline != -300 || !closing.RecordedAt.Equal(recorded) {
+		t.Errorf("We got an unexpected result: %v and %v", opening, closing)
+	}
+	if quotes := history["/boxscores/201509100nwe.htm"]; quotes[1].Spread != -5 {
+		t.Errorf("We got an unexpected result: %v instead of %v", quotes[1].Spread, -5)
+	}
+	if history, err = s.LoadLineHistory(); err != nil || len(history) != 2 || len(history["/boxscores/201509130buf.htm"]) != 1 {
+		t.Errorf("We got an unexpected result: %v, %v", history, err)
+	}
+}
+
+func TestSeasonRoundTrip(t *testing.T) {
+	s, err := Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	builder := nflwp.NewSeasonBuilder(nil)
+	builder.AddGameResult(nflwp.GameResult{Link: "/boxscores/201509100nwe.htm", Season: "2015", Week: 1, HomeTeam: "NWE", VisitingTeam: "PIT", HomeWPADJUST: 0.1, FinalWP: 1})
+	builder.AddGameResult(nflwp.GameResult{Link: "/boxscores/201509200buf.htm", Season: "2015", Week: 2, HomeTeam: "BUF", VisitingTeam: "NWE", VisitingWPADJUST: 0.3})
+	builder.TeamData["NWE"][nflwp.SPREAD] = -7
+	if err = s.SaveSeason("2015", builder); err != nil {
+		t.Fatal(err)
+	}
+	timeline, err := s.LoadTimeline("2015")
+	if err != nil || len(timeline) != 2 || timeline[0].Week != 1 || timeline[1].Week != 2 {
+		t.Fatalf("We got an unexpected result: %+v %v", timeline, err)
+	}
+	if timeline[0].TeamData["NWE"][nflwp.GAMESPLAYED] != 1 || timeline[1].TeamData["NWE"][nflwp.GAMESPLAYED] != 2 {
+		t.Errorf("We got an unexpected result: %v %v", timeline[0].TeamData["NWE"], timeline[1].TeamData["NWE"])
+	}
+	// The last week keeps what was added to the builder after its games.
+	if timeline[1].TeamData["NWE"][nflwp.SPREAD] != -7 || timeline[0].TeamData["NWE"][nflwp.SPREAD] != 0 {
+		t.Errorf("We got an unexpected result: %v %v", timeline[0].TeamData["NWE"], timeline[1].TeamData["NWE"])
+	}
+	if timeline, err = s.LoadTimeline("2016"); err != nil || len(timeline) != 0 {
+		t.Errorf("We got an unexpected result: %+v %v", timeline, err)
+	}
+	// A game from another season is an error and nothing is saved.
+	builder = nflwp.NewSeasonBuilder(nil)
+	builder.AddGameResult(nflwp.GameResult{Link: "/boxscores/201609110nwe.htm", Season: "2016", Week: 1, HomeTeam: "NWE", VisitingTeam: "PIT"})
+	builder.AddGameResult(nflwp.GameResult{Link: "/boxscores/201509270nwe.htm", Season: "2015", Week: 3, HomeTeam: "NWE", VisitingTeam: "JAX"})
+	if err = s.SaveSeason("2016", builder); err == nil {
+		t.Errorf("We expected an error for a game from another season")
+	}
+	if _, err = s.LoadGame("/boxscores/201609110nwe.htm"); err == nil {
+		t.Errorf("We should not have saved any of the season")
+	}
+	if timeline, err = s.LoadTimeline("2016"); err != nil || len(timeline) != 0 {
+		t.Errorf("We got an unexpected result: %+v %v", timeline, err)
+	}
+}
+
+func TestForeignKeys(t *testing.T) {
+	s, err := Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	_, err = s.DB().Exec(`INSERT INTO wp_points (game_link, idx, home_wp, play_info, quarter, elapsed, remaining, total_minutes)
+		VALUES ('/boxscores/201509100nwe.htm', 0, 0.5, '', 1, 0, 60, 60)`)
+	if err == nil {
+		t.Errorf("We should not be able to save a chart for a game that isn't saved")
+	}
+}
+
+func TestSeasonWithoutGames(t *testing.T) {
+	s, err := Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err = s.SaveSeason("2015", nflwp.NewSeasonBuilder(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if week, err := s.LatestWeek("2015"); err != nil || week != 0 {
+		t.Errorf("We got an unexpected result: %v %v", week, err)
+	}
+	if timeline, err := s.LoadTimeline("2015"); err != nil || len(timeline) != 0 {
+		t.Errorf("We got an unexpected result: %+v %v", timeline, err)
+	}
+}
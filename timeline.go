@@ -0,0 +1,75 @@
+package nflwp
+
+// A WeekSnapshot is the season data as it stood after a week's games.
+type WeekSnapshot struct {
+	Week     int
+	TeamData AllTeamData
+}
+
+// A SeasonTimeline is a season's data week by week, oldest first.
+// A single AllTeamData is a timeline of one week.
+type SeasonTimeline []WeekSnapshot
+
+// A copy of the AllTeamData that doesn't share any TeamData.
+func (a AllTeamData) Copy() AllTeamData {
+	Copy := make(AllTeamData, len(a))
+	for key, val := range a {
+		Copy[key] = append([]float64(nil), val...)
+	}
+	return Copy
+}
+
+// The team's per-game average of a metric, or false if the team hasn't played enough games to have one.
+// OPPWPADJUST doesn't count the team's first game, and GAMESPLAYED and GAMESWON are returned as they are.
+func (a AllTeamData) Average(Team string, Metric int) (float64, bool) {
+	val, ok := a[Team]
+	if !ok {
+		return 0, false
+	}
+	Games := val[GAMESPLAYED]
+	switch Metric {
+	case GAMESPLAYED, GAMESWON:
+		return val[Metric], true
+	case OPPWPADJUST:
+		Games--
+	}
+	if Games <= 0 {
+		return 0, false
+	}
+	return val[Metric] / Games, true
+}
+
+// Replay the season's games to find the season data after each week.
+// The games are added in the same order with the same Aggregator and Sport, so the last week is the same as TeamData.
+func (s *SeasonBuilder) Timeline() SeasonTimeline {
+	Replay := NewSeasonBuilderForSport(s.Aggregator, s.Sport)
+	var Timeline SeasonTimeline
+	for i, val := range s.Games {
+		Replay.AddGameResult(val)
+		if i == len(s.Games)-1 || s.Games[i+1].Week != val.Week {
+			Timeline = append(Timeline, WeekSnapshot{Week: val.Week, TeamData: Replay.TeamData.Copy()})
+		}
+	}
+	return Timeline
+}
+
+// The teams that have played a game in any week of the timeline, in alphabetical order. "BYE" is left out.
+func (t SeasonTimeline) Teams() []string {
+	Seen := make(AllTeamData)
+	for _, Snapshot := range t {
+		for key, val := range Snapshot.TeamData {
+			if key != "BYE" && val[GAMESPLAYED] > 0 {
+				Seen[key] = val
+			}
+		}
+	}
+	return Seen.Teams()
+}
+
+// The season data after the last week, or nil if the timeline is empty.
+func (t SeasonTimeline) Latest() AllTeamData {
+	if len(t) == 0 {
+		return nil
+	}
+	return t[len(t)-1].TeamData
+}
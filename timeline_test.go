@@ -0,0 +1,74 @@
+package nflwp
+
+import (
+	"math"
+	"testing"
+)
+
+func timelineGames() []GameResult {
+	return []GameResult{testResult(1, "NWE", "BUF", 0.1), testResult(1, "NYJ", "MIA", -0.05), testResult(2, "NWE", "NYJ", 0.2), testResult(3, "MIA", "NWE", -0.3)}
+}
+
+func TestSeasonTimeline(t *testing.T) {
+	builder := NewSeasonBuilder(nil)
+	for _, val := range timelineGames() {
+		builder.AddGameResult(val)
+	}
+	timeline := builder.Timeline()
+	if len(timeline) != 3 || timeline[0].Week != 1 || timeline[2].Week != 3 {
+		t.Fatalf("We got an unexpected result: %+v", timeline)
+	}
+	expected := []float64{1, 2, 3}
+	for i, val := range timeline {
+		if val.TeamData["NWE"][GAMESPLAYED] != expected[i] {
+			t.Errorf("We got an unexpected result: %v instead of %v", val.TeamData["NWE"][GAMESPLAYED], expected[i])
+		}
+	}
+	// The last week is the builder's season, and earlier weeks don't change with it.
+	for team, val := range builder.TeamData {
+		for i := range val {
+			if timeline.Latest()[team][i] != val[i] {
+				t.Errorf("We got an unexpected result for %v metric %v: %v instead of %v", team, i, timeline.Latest()[team][i], val[i])
+			}
+		}
+	}
+	builder.TeamData["NWE"][SPREAD] = -7
+	if timeline.Latest()["NWE"][SPREAD] != 0 {
+		t.Errorf("We got an unexpected result: the timeline shares TeamData with the builder")
+	}
+	teams := timeline.Teams()
+	if len(teams) != 4 || teams[0] != "BUF" || teams[3] != "NYJ" {
+		t.Errorf("We got an unexpected result: %v", teams)
+	}
+	if len((SeasonTimeline{}).Teams()) != 0 || (SeasonTimeline{}).Latest() != nil {
+		t.Errorf("We got an unexpected result for an empty timeline")
+	}
+}
+
+func TestAverage(t *testing.T) {
+	teamData := NewAllTeamData()
+	teamData["NWE"] = NewTeamData()
+	teamData["NWE"][GAMESPLAYED] = 2
+	teamData["NWE"][GAMESWON] = 1
+	teamData["NWE"][WPADJUST] = 0.3
+	teamData["NWE"][OPPWPADJUST] = 0.1
+	teamData["BUF"] = NewTeamData()
+	teamData["BUF"][GAMESPLAYED] = 1
+	var tests = []struct {
+		team     string
+		metric   int
+		expected float64
+		ok       bool
+	}{
+		{"NWE", WPADJUST, 0.15, true},
+		{"NWE", OPPWPADJUST, 0.1, true},
+		{"NWE", GAMESWON, 1, true},
+		{"BUF", OPPWPADJUST, 0, false},
+		{"MIA", WPADJUST, 0, false},
+	}
+	for _, test := range tests {
+		if val, ok := teamData.Average(test.team, test.metric); math.Abs(val-test.expected) > 1e-9 || ok != test.ok {
+			t.Errorf("We got an unexpected result: %v %v instead of %v %v", val, ok, test.expected, test.ok)
+		}
+	}
+}
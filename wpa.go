@@ -0,0 +1,155 @@
+package nflwp
+
+import (
+	"math"
+	"sort"
+)
+
+// A PlayWPA is the win probability a single play added for the home team.
+// Leverage is scaled so the average play of the game is 1.
+type PlayWPA struct {
+	Index    int
+	Point    WPPoint
+	WPA      float64
+	Leverage float64
+}
+
+// A GameAnalysis breaks a game's win probability chart down play by play.
+// ComebackWP is the lowest win probability the eventual winner had, and is 1 if the game ended in a tie.
+// Excitement is the total win probability that changed hands over the game.
+type GameAnalysis struct {
+	HomeTeam     string
+	VisitingTeam string
+	Plays        []PlayWPA
+	ComebackWP   float64
+	Excitement   float64
+	winner       string
+}
+
+// Given a game with its chart, find the WPA and leverage of each play.
+func AnalyzeGame(Result GameResult) GameAnalysis {
+	return AnalyzeGameForSport(Result, NFL)
+}
+
+// Like AnalyzeGame, but the leverage is found with LeverageForSport.
+func AnalyzeGameForSport(Result GameResult, ThisSport Sport) GameAnalysis {
+	Analysis := GameAnalysis{HomeTeam: Result.HomeTeam, VisitingTeam: Result.VisitingTeam, ComebackWP: 1}
+	if len(Result.Points) == 0 {
+		return Analysis
+	}
+	Analysis.Plays = make([]PlayWPA, len(Result.Points))
+	Total := 0.0
+	for i, val := range Result.Points {
+		Analysis.Plays[i] = PlayWPA{Index: i, Point: val, Leverage: LeverageForSport(val.HomeWP, val.Remaining, val.TotalMinutes, ThisSport)}
+		if i > 0 {
+			Analysis.Plays[i].WPA = val.HomeWP - Result.Points[i-1].HomeWP
+		}
+		Analysis.Excitement += math.Abs(Analysis.Plays[i].WPA)
+		Total += Analysis.Plays[i].Leverage
+	}
+	for i := range Analysis.Plays {
+		Analysis.Plays[i].Leverage /= Total / float64(len(Analysis.Plays))
+	}
+	Final := Result.Points[len(Result.Points)-1].HomeWP
+	if Final == 1 || Final == 0 {
+		Analysis.winner = Result.VisitingTeam
+		if Final == 1 {
+			Analysis.winner = Result.HomeTeam
+		}
+		for _, val := range Result.Points {
+			WinnerWP := val.HomeWP
+			if Final == 0 {
+				WinnerWP = 1 - WinnerWP
+			}
+			Analysis.ComebackWP = math.Min(Analysis.ComebackWP, WinnerWP)
+		}
+	}
+	return Analysis
+}
+
+// Analyze every game in the list.
+func AnalyzeGames(Games []GameResult) []GameAnalysis {
+	return AnalyzeGamesForSport(Games, NFL)
+}
+
+// Like AnalyzeGames, but with AnalyzeGameForSport.
+func AnalyzeGamesForSport(Games []GameResult, ThisSport Sport) []GameAnalysis {
+	Analyses := make([]GameAnalysis, len(Games))
+	for i, val := range Games {
+		Analyses[i] = AnalyzeGameForSport(val, ThisSport)
+	}
+	return Analyses
+}
+
+// The team that won the game, or "" for a tie.
+func (g GameAnalysis) Winner() string {
+	return g.winner
+}
+
+// The N plays with the biggest swing in win probability, either way, biggest first.
+func (g GameAnalysis) BiggestSwings(N int) []PlayWPA {
+	Plays := append([]PlayWPA(nil), g.Plays...)
+	sort.SliceStable(Plays, func(i, j int) bool { return math.Abs(Plays[i].WPA) > math.Abs(Plays[j].WPA) })
+	if N < len(Plays) {
+		Plays = Plays[:N]
+	}
+	return Plays
+}
+
+// A TeamWPASummary adds up a team's games for a season.
+// WPA is the total the team added from kickoff to the final whistle, Excitement is the average
+// excitement of its games and Comebacks counts wins where the team's win probability dropped below ComebackThreshold.
+type TeamWPASummary struct {
+	Team         string
+	Games        int
+	Wins         int
+	WPA          float64
+	Excitement   float64
+	Comebacks    int
+	LowestWinWP  float64
+	BiggestSwing PlayWPA
+}
+
+// A win is a comeback if the winner's win probability dropped below this.
+const ComebackThreshold = 0.2
+
+// Given the analyses of every game in a season, summarize them per team.
+// BiggestSwing is the play with the biggest swing in the team's favor, with its WPA from the team's point of view.
+func SummarizeWPA(Games []GameAnalysis) map[string]*TeamWPASummary {
+	Summary := make(map[string]*TeamWPASummary)
+	get := func(Team string) *TeamWPASummary {
+		if _, ok := Summary[Team]; !ok {
+			Summary[Team] = &TeamWPASummary{Team: Team, LowestWinWP: 1}
+		}
+		return Summary[Team]
+	}
+	for _, Game := range Games {
+		for _, Team := range []string{Game.HomeTeam, Game.VisitingTeam} {
+			Sign := 1.0
+			if Team == Game.VisitingTeam {
+				Sign = -1.0
+			}
+			ThisTeam := get(Team)
+			ThisTeam.Games++
+			ThisTeam.Excitement += Game.Excitement
+			for _, val := range Game.Plays {
+				ThisTeam.WPA += Sign * val.WPA
+				if Sign*val.WPA > ThisTeam.BiggestSwing.WPA {
+					ThisTeam.BiggestSwing = val
+					ThisTeam.BiggestSwing.WPA = Sign * val.WPA
+				}
+			}
+			if Game.Winner() == Team {
+				ThisTeam.Wins++
+				ThisTeam.LowestWinWP = math.Min(ThisTeam.LowestWinWP, Game.ComebackWP)
+				if Game.ComebackWP < ComebackThreshold {
+					ThisTeam.Comebacks++
+				}
+			}
+		}
+	}
+	for _, val := range Summary {
+		val.Excitement /= float64(val.Games)
+	}
+	return Summary
+}
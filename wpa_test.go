@@ -0,0 +1,53 @@
+package nflwp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnalyzeGame(t *testing.T) {
+	points := ParseChartData([]byte(testChartData))
+	analysis := AnalyzeGame(GameResult{HomeTeam: "GNB", VisitingTeam: "CHI", Points: points})
+	expectedWPA := []float64{0, 0.1, -0.2, 0.5}
+	for i := 0; i < len(expectedWPA); i++ {
+		if math.Abs(analysis.Plays[i].WPA-expectedWPA[i]) > 0.0005 {
+			t.Errorf("We got an unexpected result: %v instead of %v", analysis.Plays[i].WPA, expectedWPA[i])
+		}
+	}
+	if analysis.Winner() != "GNB" || math.Abs(analysis.ComebackWP-0.5) > 0.0005 || math.Abs(analysis.Excitement-0.8) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v %v %v", analysis.Winner(), analysis.ComebackWP, analysis.Excitement)
+	}
+	if swings := analysis.BiggestSwings(2); len(swings) != 2 || swings[0].Index != 3 || swings[1].Index != 2 {
+		t.Errorf("We got an unexpected result: %+v", swings)
+	}
+	leverage := 0.0
+	for _, val := range analysis.Plays {
+		leverage += val.Leverage
+	}
+	if math.Abs(leverage/4-1) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", leverage/4, 1)
+	}
+	// The leverage is relative to the game's average play, so the sport's margins scale every play the same.
+	college := AnalyzeGameForSport(GameResult{HomeTeam: "GNB", VisitingTeam: "CHI", Points: points}, NCAAF)
+	for i, val := range college.Plays {
+		if math.Abs(val.Leverage-analysis.Plays[i].Leverage) > 1e-9 {
+			t.Errorf("We got an unexpected result: %v instead of %v", val.Leverage, analysis.Plays[i].Leverage)
+		}
+	}
+}
+
+func TestSummarizeWPA(t *testing.T) {
+	points := ParseChartData([]byte(testChartData))
+	comeback := []WPPoint{{HomeWP: 0.5}, {HomeWP: 0.1}, {HomeWP: 1}}
+	summary := SummarizeWPA(AnalyzeGames([]GameResult{
+		{HomeTeam: "GNB", VisitingTeam: "CHI", Points: points},
+		{HomeTeam: "CHI", VisitingTeam: "GNB", Points: comeback},
+	}))
+	if summary["GNB"].Games != 2 || summary["GNB"].Wins != 1 || summary["CHI"].Comebacks != 1 || math.Abs(summary["CHI"].LowestWinWP-0.1) > 0.0005 {
+		t.Errorf("We got an unexpected result: %+v %+v", summary["GNB"], summary["CHI"])
+	}
+	// GNB added 0.4 in the first game and lost 0.5 in the second.
+	if math.Abs(summary["GNB"].WPA+0.1) > 0.0005 || math.Abs(summary["CHI"].BiggestSwing.WPA-0.9) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v %v", summary["GNB"].WPA, summary["CHI"].BiggestSwing.WPA)
+	}
+}
@@ -0,0 +1,174 @@
+package nflwp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// How the WP difference at each point of a game is weighted when averaging it into WPADJUST.
+type AdjustmentMethod int
+
+const (
+	PERPLAY   AdjustmentMethod = iota // Every chartData point counts the same
+	PERMINUTE                         // Each point counts for the game clock that runs until the next point
+	LEVERAGE                          // Each point counts for how much a score would swing the game at that moment
+)
+
+// A WPPoint is one point of a game's win probability chart.
+// HomeWP is the home team's win probability and PlayInfo is the raw text PFR shows for the point,
+// for example "\"Q1 5:00 GNB 0-CHI 0 32.20%\"".
+// Quarter is one past the last period in overtime. Elapsed and Remaining are minutes of game clock and TotalMinutes is
+// how long the game is at that point, which a timed overtime makes longer, for example 75 in an NFL overtime and 60 otherwise.
+type WPPoint struct {
+	HomeWP       float64
+	PlayInfo     string
+	Quarter      float64
+	Elapsed      float64
+	Remaining    float64
+	TotalMinutes float64
+}
+
+// Given the HTML text of a gamelink, parse the chartData into WPPoints.
+// Points whose clock can't be read keep the clock of the point before them.
+// Returns nil on error.
+func ParseChartData(body []byte) []WPPoint {
+	return ParseChartDataForSport(body, NFL)
+}
+
+// Like ParseChartData, but the clocks are read with the sport's periods.
+func ParseChartDataForSport(body []byte, ThisSport Sport) []WPPoint {
+	Data := FindAllBetween(body, "var chartData = ", "\n")
+	if Data == nil {
+		return nil
+	}
+	Data[0] = strings.Replace(Data[0], "var chartData = ", "", -1)
+	Data = strings.Split(Data[0][2:len(Data[0])-2], "],[")
+	Points := make([]WPPoint, len(Data))
+	Previous := WPPoint{Quarter: 1, Remaining: ThisSport.GameMinutes(), TotalMinutes: ThisSport.GameMinutes()}
+	for i, val := range Data {
+		ThisPlay := strings.Split(val, ",")
+		if len(ThisPlay) < 3 {
+			fmt.Println("Error: not enough fields in chartData point", val)
+			return nil
+		}
+		HomeWP, err := strconv.ParseFloat(ThisPlay[1], 64)
+		if err != nil {
+			fmt.Println("Error: ", err)
+			return nil
+		}
+		Points[i] = Previous
+		Points[i].HomeWP = HomeWP
+		Points[i].PlayInfo = ThisPlay[2]
+		if Quarter, Remaining, Total, ok := ThisSport.ParsePlayClock(ThisPlay[2]); ok {
+			Points[i].Quarter = Quarter
+			Points[i].Remaining = Remaining
+			Points[i].TotalMinutes = Total
+			Points[i].Elapsed = Total - Remaining
+		}
+		Previous = Points[i]
+	}
+	return Points
+}
+
+// Given the info for a play, like "\"Q3 10:00 ...\"", return the quarter, the minutes left in the game and how long the game is.
+// Overtime is quarter 5 and makes the game 75 minutes. See Sport.ParsePlayClock for other sports.
+func ParsePlayClock(PlayInfo string) (float64, float64, float64, bool) {
+	return NFL.ParsePlayClock(PlayInfo)
+}
+
+// Given a quarter and clock, like "Q3 2:30" or "OT 4:00", return the minutes left in an NFL game the way ParsePlayClock counts them.
+func ParseGameClock(Clock string) (float64, bool) {
+	return NFL.ParseGameClock(Clock)
+}
+
+// Return x so that cdf(x, 0, 1) = p.
+func inverseCDF(p float64) float64 {
+	Low, High := -10.0, 10.0
+	for count := 0; count < 100; count++ {
+		Mid := (Low + High) / 2
+		if cdf(Mid, 0, 1) < p {
+			Low = Mid
+		} else {
+			High = Mid
+		}
+	}
+	return (Low + High) / 2
+}
+
+// How much a point of score margin is worth to the win probability at this point of the game.
+// Under the same normal model as WinProbability, this is the density at the current win probability
+// divided by the standard deviation of the margin over the time remaining.
+// It is largest in close games late and smallest in blowouts.
+func Leverage(HomeWP, Remaining, TotalMinutes float64) float64 {
+	return LeverageForSport(HomeWP, Remaining, TotalMinutes, NFL)
+}
+
+// Like Leverage, but with the sport's MarginStdDev.
+func LeverageForSport(HomeWP, Remaining, TotalMinutes float64, ThisSport Sport) float64 {
+	HomeWP = math.Min(math.Max(HomeWP, 1e-6), 1-1e-6)
+	// Don't let the last seconds blow up to infinity.
+	Remaining = math.Max(Remaining, 0.5)
+	z := inverseCDF(HomeWP)
+	return math.Exp(-z*z/2) / math.Sqrt(2*math.Pi) / (ThisSport.MarginStdDev * math.Sqrt(Remaining/TotalMinutes))
+}
+
+// The weight of each point in a game for the given method.
+// If the weights add up to zero, for example a chart with no clock, every point gets the same weight.
+func AdjustmentWeights(Points []WPPoint, Method AdjustmentMethod) []float64 {
+	return AdjustmentWeightsForSport(Points, Method, NFL)
+}
+
+// Like AdjustmentWeights, but LEVERAGE is found with LeverageForSport.
+func AdjustmentWeightsForSport(Points []WPPoint, Method AdjustmentMethod, ThisSport Sport) []float64 {
+	Weights := make([]float64, len(Points))
+	Total := 0.0
+	for i, val := range Points {
+		switch Method {
+		case PERMINUTE:
+			if i+1 < len(Points) {
+				Weights[i] = math.Max(Points[i+1].Elapsed-val.Elapsed, 0)
+			}
+		case LEVERAGE:
+			Weights[i] = LeverageForSport(val.HomeWP, val.Remaining, val.TotalMinutes, ThisSport)
+		default:
+			Weights[i] = 1
+		}
+		Total += Weights[i]
+	}
+	if Total == 0 {
+		for i := range Weights {
+			Weights[i] = 1
+		}
+	}
+	return Weights
+}
+
+// Fill in the game's adjustments from the points of its chart, weighted with the given method.
+// WPADJUST compares each point to the win probability the spread alone predicts at that time
+// and STRAIGHTWPADJUST compares it to the pregame win probability.
+func (g *GameResult) SetAdjustments(Points []WPPoint, Method AdjustmentMethod) {
+	g.SetAdjustmentsForSport(Points, Method, NFL)
+}
+
+// Like SetAdjustments, but the spread decays over the length of a game in the given sport.
+func (g *GameResult) SetAdjustmentsForSport(Points []WPPoint, Method AdjustmentMethod, ThisSport Sport) {
+	var Adjustment, Home, Straight, Total float64
+	if len(Points) == 0 {
+		return
+	}
+	Starting := Points[0].HomeWP
+	Weights := AdjustmentWeightsForSport(Points, Method, ThisSport)
+	for i, val := range Points {
+		Adjustment = ThisSport.AdjustedStartingProbability(g.Spread, val.PlayInfo, Adjustment)
+		Home += Weights[i] * (val.HomeWP - Adjustment)
+		Straight += Weights[i] * (val.HomeWP - Starting + 0.5)
+		Total += Weights[i]
+	}
+	g.HomeWPADJUST = Home / Total
+	g.VisitingWPADJUST = -g.HomeWPADJUST
+	g.HomeSTRAIGHTWPADJUST = Straight / Total
+	g.VisitingSTRAIGHTWPADJUST = 1 - g.HomeSTRAIGHTWPADJUST
+	g.Plays = len(Points)
+}
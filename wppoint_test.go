@@ -0,0 +1,95 @@
+package nflwp
+
+import (
+	"math"
+	"testing"
+)
+
+const testChartData = "var chartData = [[1,0.6,\"Q1 15:00 GNB 0-CHI 0 60.00%\"],[2,0.7,\"Q2 15:00 GNB 7-CHI 0 70.00%\"],[3,0.5,\"Q4 15:00 GNB 7-CHI 7 50.00%\"],[4,1,\"Q4 0:00 GNB 14-CHI 7 100.00%\"]]\n"
+
+func TestParsePlayClock(t *testing.T) {
+	infos := []string{"\"Q1 15:00 GNB 0-CHI 0\"", "\"Q3 2:30 GNB 0-CHI 0\"", "\"OT 10:00 GNB 0-CHI 0\"", "null", "  "}
+	expectedRemaining := []float64{60, 17.5, 10, -1, -1}
+	expectedTotal := []float64{60, 60, 75, -1, -1}
+	for i := 0; i < len(infos); i++ {
+		_, remaining, total, ok := ParsePlayClock(infos[i])
+		if expectedRemaining[i] < 0 {
+			if ok {
+				t.Errorf("We got an unexpected result: %v should not parse", infos[i])
+			}
+			continue
+		}
+		if !ok || math.Abs(remaining-expectedRemaining[i]) > 0.0005 || total != expectedTotal[i] {
+			t.Errorf("We got an unexpected result: %v of %v instead of %v of %v", remaining, total, expectedRemaining[i], expectedTotal[i])
+		}
+	}
+	// College overtime has no clock, and a 10 minute overtime makes a 70 minute game.
+	shortOT := NFL
+	shortOT.OvertimeMinutes = 10
+	if quarter, remaining, total, ok := NCAAF.ParsePlayClock("\"OT GNB 0-CHI 0\""); !ok || quarter != 5 || remaining != 0 || total != 60 {
+		t.Errorf("We got an unexpected result: %v %v of %v", quarter, remaining, total)
+	}
+	if quarter, remaining, total, ok := shortOT.ParsePlayClock("\"OT 4:00 GNB 0-CHI 0\""); !ok || quarter != 5 || remaining != 4 || total != 70 {
+		t.Errorf("We got an unexpected result: %v %v of %v", quarter, remaining, total)
+	}
+	if _, remaining, total, ok := NCAAF.ParsePlayClock("\"Q2 7:30 GNB 0-CHI 0\""); !ok || remaining != 37.5 || total != 60 {
+		t.Errorf("We got an unexpected result: %v of %v", remaining, total)
+	}
+}
+
+func TestParseGameClock(t *testing.T) {
+	clocks := []string{"Q1 15:00", "q3 2:30", " OT 10:00 ", "halftime"}
+	expectedResults := []float64{60, 17.5, 10, -1}
+	for i := 0; i < len(clocks); i++ {
+		result, ok := ParseGameClock(clocks[i])
+		if ok != (expectedResults[i] >= 0) || (ok && math.Abs(result-expectedResults[i]) > 0.0005) {
+			t.Errorf("We got an unexpected result: %v %v instead of %v", result, ok, expectedResults[i])
+		}
+	}
+}
+
+func TestAdjustmentWeights(t *testing.T) {
+	points := ParseChartData([]byte(testChartData))
+	if len(points) != 4 || points[2].Elapsed != 45 {
+		t.Fatalf("We got an unexpected result: %+v", points)
+	}
+	weights := AdjustmentWeights(points, PERMINUTE)
+	expectedResults := []float64{15, 30, 15, 0}
+	for i := 0; i < len(weights); i++ {
+		if weights[i] != expectedResults[i] {
+			t.Errorf("We got an unexpected result: %v instead of %v", weights[i], expectedResults[i])
+		}
+	}
+	for _, val := range AdjustmentWeights(points, PERPLAY) {
+		if val != 1 {
+			t.Errorf("We got an unexpected result: %v instead of %v", val, 1)
+		}
+	}
+	// A tie game late matters more than the same game early, and a blowout matters less than either.
+	if Leverage(0.5, 2, 60) <= Leverage(0.5, 50, 60) || Leverage(0.99, 2, 60) >= Leverage(0.5, 50, 60) {
+		t.Errorf("We got an unexpected result: %v %v %v", Leverage(0.5, 2, 60), Leverage(0.5, 50, 60), Leverage(0.99, 2, 60))
+	}
+	// A point of margin is worth less when margins are wider.
+	if nfl, ncaaf := Leverage(0.5, 2, 60), LeverageForSport(0.5, 2, 60, NCAAF); math.Abs(ncaaf-nfl*STDDEV/NCAAF.MarginStdDev) > 1e-12 {
+		t.Errorf("We got an unexpected result: %v for NCAAF and %v for the NFL", ncaaf, nfl)
+	}
+}
+
+func TestSetAdjustments(t *testing.T) {
+	points := ParseChartData([]byte(testChartData))
+	perPlay := GameResult{Spread: -3}
+	perPlay.SetAdjustments(points, PERPLAY)
+	perMinute := GameResult{Spread: -3}
+	perMinute.SetAdjustments(points, PERMINUTE)
+	// The straight adjustment is the average of 0.5, 0.6, 0.4 and 0.9 per play,
+	// and 0.5, 0.6 and 0.4 for 15, 30 and 15 minutes per minute.
+	if math.Abs(perPlay.HomeSTRAIGHTWPADJUST-0.6) > 0.0005 || math.Abs(perPlay.VisitingSTRAIGHTWPADJUST-0.4) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", perPlay.HomeSTRAIGHTWPADJUST, 0.6)
+	}
+	if math.Abs(perMinute.HomeSTRAIGHTWPADJUST-0.525) > 0.0005 {
+		t.Errorf("We got an unexpected result: %v instead of %v", perMinute.HomeSTRAIGHTWPADJUST, 0.525)
+	}
+	if perPlay.Plays != 4 || perPlay.HomeWPADJUST != -perPlay.VisitingWPADJUST {
+		t.Errorf("We got an unexpected result: %+v", perPlay)
+	}
+}